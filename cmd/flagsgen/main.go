@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xhd2015/less-gen/flags"
+	"github.com/xhd2015/less-gen/flags/flagsgen"
+)
+
+func main() {
+	err := handle(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func handle(args []string) error {
+	var file string
+	var helpConst string
+	var parseArgs string
+	var check bool
+
+	_, err := flags.New().Name("flagsgen").
+		Description("flagsgen generates the flags builder chain from a help text,\nread from the constant of a Go file or from a plain text file").
+		String("--const", &helpConst).Placeholder("NAME").Desc("name of the help constant in a Go file").Default("help").
+		String("--args", &parseArgs).Placeholder("EXPR").Desc("expression of the args to parse").Default("args").
+		Bool("--check", &check).Desc("report where the help text and the builder chain disagree").
+		Help("-h,--help", "").
+		Arg("FILE", &file).
+		Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if check {
+		problems, err := flagsgen.CheckFile(file, helpConst)
+		if err != nil {
+			return err
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("%s", strings.Join(problems, "\n"))
+		}
+		return nil
+	}
+
+	help, err := readHelp(file, helpConst)
+	if err != nil {
+		return err
+	}
+	fmt.Print(flagsgen.Generate(flagsgen.ParseHelp(help), flagsgen.Config{
+		HelpConst: helpConst,
+		Args:      parseArgs,
+	}))
+	return nil
+}
+
+func readHelp(file string, helpConst string) (string, error) {
+	if filepath.Ext(file) == ".go" {
+		return flagsgen.ReadString(file, helpConst)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
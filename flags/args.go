@@ -0,0 +1,148 @@
+package flags
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ArgSpec represents a declared positional argument
+type ArgSpec struct {
+	Name     string      // name shown in help and errors, like PKG
+	Target   interface{} // pointer to the target variable
+	Type     FlagType    // type inferred from the target
+	Value    Value       // parses the argument into Target
+	Optional bool        // whether the argument can be omitted
+	Variadic bool        // whether the argument takes all remaining args
+}
+
+// Arg creates a new builder and adds a required positional argument
+func Arg(name string, target interface{}) *Builder {
+	return (&Builder{}).Arg(name, target)
+}
+
+// OptionalArg creates a new builder and adds an optional positional argument
+func OptionalArg(name string, target interface{}) *Builder {
+	return (&Builder{}).OptionalArg(name, target)
+}
+
+// Args creates a new builder and adds a variadic positional argument
+func Args(name string, target interface{}) *Builder {
+	return (&Builder{}).Args(name, target)
+}
+
+// Arg adds a required positional argument. The type is inferred
+// from target, which accepts the same types as BindStruct fields.
+//
+//	flags.Arg("PKG", &pkg).Args("FILES", &files).Parse(args)
+func (b *Builder) Arg(name string, target interface{}) *Builder {
+	b.addArg(name, target, false, false)
+	return b
+}
+
+// OptionalArg adds a positional argument that can be omitted,
+// it must not be followed by required arguments
+func (b *Builder) OptionalArg(name string, target interface{}) *Builder {
+	b.addArg(name, target, true, false)
+	return b
+}
+
+// Args adds a variadic positional argument collecting all remaining
+// args into the slice target, it must be the last argument
+func (b *Builder) Args(name string, target interface{}) *Builder {
+	b.addArg(name, target, true, true)
+	return b
+}
+
+func (b *Builder) addArg(name string, target interface{}, optional bool, variadic bool) {
+	spec := ArgSpec{
+		Name:     name,
+		Target:   target,
+		Optional: optional,
+		Variadic: variadic,
+	}
+	if n := len(b.argSpecs); n > 0 {
+		last := b.argSpecs[n-1]
+		if last.Variadic {
+			b.setErr(fmt.Errorf("argument %s: cannot follow variadic argument %s", name, last.Name))
+		} else if last.Optional && !optional {
+			b.setErr(fmt.Errorf("argument %s: required argument cannot follow optional argument %s", name, last.Name))
+		}
+	}
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Ptr {
+		b.setErr(fmt.Errorf("argument %s: target must be a pointer", name))
+	} else if flagType, ok := flagTypeOf(t.Elem()); !ok {
+		b.setErr(fmt.Errorf("argument %s: unsupported type %v", name, t.Elem()))
+	} else if isSliceType(flagType) != variadic {
+		if variadic {
+			b.setErr(fmt.Errorf("argument %s: variadic target must be a slice, actual: %v", name, t))
+		} else {
+			b.setErr(fmt.Errorf("argument %s: slice target requires Args, actual: %v", name, t))
+		}
+	} else {
+		value, err := newValue(flagType, target)
+		if err != nil {
+			b.setErr(fmt.Errorf("argument %s: %v", name, err))
+		}
+		spec.Type = flagType
+		spec.Value = value
+	}
+	b.argSpecs = append(b.argSpecs, spec)
+}
+
+func isSliceType(t FlagType) bool {
+	return t == FlagTypeStringSlice || t == FlagTypeIntSlice
+}
+
+// bindArgs assigns args to the declared positional arguments,
+// it does nothing if no argument is declared. indexes are the
+// positions of args in the parsed command line.
+func (b *Builder) bindArgs(args []string, indexes []int) error {
+	if len(b.argSpecs) == 0 {
+		return nil
+	}
+	i := 0
+	for _, spec := range b.argSpecs {
+		if spec.Variadic {
+			for ; i < len(args); i++ {
+				err := spec.Value.Set(args[i])
+				if err != nil {
+					return &ParseError{Kind: KindInvalidArg, Name: spec.Name, Index: indexes[i], Err: err}
+				}
+			}
+			break
+		}
+		if i >= len(args) {
+			if spec.Optional {
+				break
+			}
+			return &ParseError{Kind: KindMissingArg, Name: spec.Name, Index: -1}
+		}
+		err := spec.Value.Set(args[i])
+		if err != nil {
+			return &ParseError{Kind: KindInvalidArg, Name: spec.Name, Index: indexes[i], Err: err}
+		}
+		i++
+	}
+	if i < len(args) {
+		return &ParseError{Kind: KindExtraArg, Name: args[i], Index: indexes[i]}
+	}
+	return nil
+}
+
+// argsUsage renders the arguments like PKG [DIR] [FILES...]
+func (b *Builder) argsUsage() string {
+	parts := make([]string, 0, len(b.argSpecs))
+	for _, spec := range b.argSpecs {
+		switch {
+		case spec.Variadic:
+			parts = append(parts, "["+spec.Name+"...]")
+		case spec.Optional:
+			parts = append(parts, "["+spec.Name+"]")
+		default:
+			parts = append(parts, spec.Name)
+		}
+	}
+	return strings.Join(parts, " ")
+}
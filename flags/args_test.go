@@ -0,0 +1,103 @@
+package flags
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArgs(t *testing.T) {
+	var pkg string
+	var count int
+	var files []string
+	var verbose bool
+
+	remainArgs, err := Bool("-v", &verbose).
+		Arg("PKG", &pkg).
+		OptionalArg("COUNT", &count).
+		Args("FILES", &files).
+		Parse([]string{"./api", "-v", "3", "a.go", "b.go"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if pkg != "./api" || count != 3 || strings.Join(files, ",") != "a.go,b.go" || !verbose {
+		t.Errorf("Unexpected values: pkg=%q count=%d files=%v verbose=%v", pkg, count, files, verbose)
+	}
+	if len(remainArgs) != 4 {
+		t.Errorf("Expected all positional args to be returned, got %v", remainArgs)
+	}
+}
+
+func TestArgs_Optional(t *testing.T) {
+	var pkg, dir string
+	dir = "."
+	_, err := Arg("PKG", &pkg).OptionalArg("DIR", &dir).Parse([]string{"x"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if pkg != "x" || dir != "." {
+		t.Errorf("Expected pkg=x and dir unchanged, got pkg=%q dir=%q", pkg, dir)
+	}
+}
+
+func TestArgs_Errors(t *testing.T) {
+	var pkg string
+	var count int
+	tests := []struct {
+		args   []string
+		expect string
+	}{
+		{nil, "missing argument PKG"},
+		{[]string{"x"}, "missing argument COUNT"},
+		{[]string{"x", "abc"}, "invalid argument COUNT"},
+		{[]string{"x", "1", "extra"}, "unexpected argument: extra"},
+	}
+	for _, tt := range tests {
+		_, err := Arg("PKG", &pkg).Arg("COUNT", &count).Parse(tt.args)
+		if err == nil || !strings.Contains(err.Error(), tt.expect) {
+			t.Errorf("Parse(%v): expected error containing %q, got: %v", tt.args, tt.expect, err)
+		}
+	}
+}
+
+func TestArgs_DeclarationErrors(t *testing.T) {
+	var a, b string
+	var files []string
+	tests := []struct {
+		builder *Builder
+		expect  string
+	}{
+		{OptionalArg("A", &a).Arg("B", &b), "required argument cannot follow optional argument A"},
+		{Args("FILES", &files).Arg("B", &b), "cannot follow variadic argument FILES"},
+		{Arg("FILES", &files), "slice target requires Args"},
+		{Args("A", &a), "variadic target must be a slice"},
+		{Arg("A", a), "target must be a pointer"},
+	}
+	for _, tt := range tests {
+		_, err := tt.builder.Parse(nil)
+		if err == nil || !strings.Contains(err.Error(), tt.expect) {
+			t.Errorf("expected error containing %q, got: %v", tt.expect, err)
+		}
+	}
+}
+
+func TestArgs_Command(t *testing.T) {
+	var pkg string
+	_, err := Command("gen", func(c *Builder) {
+		c.Arg("PKG", &pkg).Handle(func(args []string) error { return nil })
+	}).Parse([]string{"gen", "./api"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if pkg != "./api" {
+		t.Errorf("Expected pkg=./api, got %q", pkg)
+	}
+}
+
+func TestArgs_Usage(t *testing.T) {
+	var pkg string
+	var files []string
+	usage := Arg("PKG", &pkg).Args("FILES", &files).Name("tool").Usage()
+	if !strings.HasPrefix(usage, "Usage: tool [OPTIONS] PKG [FILES...]\n") {
+		t.Errorf("Expected args in usage line, got:\n%s", usage)
+	}
+}
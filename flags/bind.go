@@ -0,0 +1,174 @@
+package flags
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// BindStruct creates a new builder and adds flags from struct tags
+func BindStruct(opts interface{}) *Builder {
+	return (&Builder{}).BindStruct(opts)
+}
+
+// BindStruct adds a flag for every tagged field of the struct
+// pointed to by opts. Recognized tags:
+//
+//	flag:"-v,--verbose"   flag names, required to bind a field
+//	help:"..."            description, see Desc
+//	default:"..."         default value, see Default
+//	env:"A,B"             environment variables, see Env
+//	placeholder:"FILE"    value name, see Placeholder
+//	enum:"a,b,c"          allowed values of a string field, see Enum
+//	sep:","               splits values of slice and map fields, see Split
+//	required:"true"       the flag must be set, see Required
+//	prefix:"db-"          on struct fields, prepended to nested long names
+//
+// Untagged struct fields, embedded or not, are walked recursively
+// so option structs can be composed. A prefix only applies to long
+// names: --host in a field tagged prefix:"db-" becomes --db-host.
+//
+// Field types follow the fluent methods: bool, string, integers,
+// floats, time.Duration, time.Time, []string, []int,
+// map[string]string, and pointers to them. Errors in the struct
+// definition are reported by Parse.
+func (b *Builder) BindStruct(opts interface{}) *Builder {
+	v := reflect.ValueOf(opts)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		b.setErr(fmt.Errorf("BindStruct: expect pointer to struct, actual: %T", opts))
+		return b
+	}
+	err := b.bindStruct(v.Elem(), "")
+	if err != nil {
+		b.setErr(fmt.Errorf("BindStruct: %v", err))
+	}
+	return b
+}
+
+func (b *Builder) bindStruct(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			// unexported
+			continue
+		}
+		flagTag, hasFlag := field.Tag.Lookup("flag")
+		if flagTag == "-" {
+			continue
+		}
+		fieldValue := v.Field(i)
+		if !hasFlag {
+			nested, ok := structValue(fieldValue)
+			if !ok {
+				continue
+			}
+			err := b.bindStruct(nested, prefix+field.Tag.Get("prefix"))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if !fieldValue.CanSet() {
+			return fmt.Errorf("field %s: cannot bind unexported field", field.Name)
+		}
+		flagType, ok := flagTypeOf(field.Type)
+		if !ok {
+			return fmt.Errorf("field %s: unsupported type %v", field.Name, field.Type)
+		}
+		choices := parseNames(field.Tag.Get("enum"))
+		if len(choices) > 0 {
+			if flagType != FlagTypeString {
+				return fmt.Errorf("field %s: enum requires a string field", field.Name)
+			}
+			flagType = FlagTypeEnum
+		}
+		names := parseNames(flagTag)
+		if len(names) == 0 {
+			return fmt.Errorf("field %s: empty flag names", field.Name)
+		}
+		for j, name := range names {
+			if prefix != "" && strings.HasPrefix(name, "--") {
+				names[j] = "--" + prefix + name[2:]
+			}
+		}
+		spec := b.addFlag(names, flagType, fieldValue.Addr().Interface())
+		spec.Description = field.Tag.Get("help")
+		spec.Placeholder = field.Tag.Get("placeholder")
+		spec.Default = field.Tag.Get("default")
+		spec.EnvVars = parseNames(field.Tag.Get("env"))
+		spec.Separator = field.Tag.Get("sep")
+		spec.Required = field.Tag.Get("required") == "true"
+		if v, ok := spec.Value.(enumValue); ok {
+			v.choices = choices
+			spec.Value = v
+		}
+	}
+	return nil
+}
+
+// structValue returns the struct behind v, allocating nil
+// pointers to structs on the way. Exported fields of embedded
+// unexported structs are still settable, so only addressability
+// is required here.
+func structValue(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.Struct {
+		if v.IsNil() {
+			if !v.CanSet() {
+				return reflect.Value{}, false
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || !v.CanAddr() || v.Type() == timeType {
+		return reflect.Value{}, false
+	}
+	return v, true
+}
+
+// flagTypeOf maps a field type to the flag type whose setter
+// accepts a pointer to it, T and *T are both supported
+func flagTypeOf(t reflect.Type) (FlagType, bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case durationType:
+		return FlagTypeDuration, true
+	case timeType:
+		return FlagTypeTime, true
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return FlagTypeBool, true
+	case reflect.String:
+		return FlagTypeString, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return FlagTypeInt, true
+	case reflect.Float32, reflect.Float64:
+		return FlagTypeFloat, true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String {
+			return FlagTypeStringSlice, true
+		}
+		if isInt(t.Elem()) {
+			return FlagTypeIntSlice, true
+		}
+	case reflect.Map:
+		if t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.String {
+			return FlagTypeStringMap, true
+		}
+	}
+	return 0, false
+}
+
+// setErr records the first configuration error on the root
+// builder, it is returned by Parse
+func (b *Builder) setErr(err error) {
+	root := b.root()
+	if root.err == nil {
+		root.err = err
+	}
+}
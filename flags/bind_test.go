@@ -0,0 +1,104 @@
+package flags
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindCommonOptions struct {
+	Verbose bool `flag:"-v,--verbose" help:"enable verbose output"`
+}
+
+type bindDBOptions struct {
+	Host string `flag:"--host" default:"localhost" help:"database host"`
+	Port *int   `flag:"--port" env:"TEST_DB_PORT"`
+}
+
+type bindOptions struct {
+	bindCommonOptions
+
+	Timeout time.Duration  `flag:"--timeout" default:"30s" help:"set timeout duration"`
+	Files   []string       `flag:"--file" placeholder:"FILE" help:"add files to process"`
+	Level   *string        `flag:"--level"`
+	Retry   uint8          `flag:"--retry"`
+	DB      bindDBOptions  `prefix:"db-"`
+	Cache   *bindDBOptions `prefix:"cache-"`
+	Ignored string
+	Skipped string `flag:"-"`
+}
+
+func TestBindStruct(t *testing.T) {
+	t.Setenv("TEST_DB_PORT", "5432")
+
+	var opts bindOptions
+	remainArgs, err := BindStruct(&opts).Parse([]string{
+		"-v",
+		"--file", "a.go", "--file", "b.go",
+		"--level", "debug",
+		"--retry", "3",
+		"--db-host", "db.local",
+		"--cache-host", "cache.local",
+		"rest",
+	})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !opts.Verbose {
+		t.Errorf("Expected embedded verbose=true")
+	}
+	if opts.Timeout != 30*time.Second {
+		t.Errorf("Expected default timeout=30s, got %v", opts.Timeout)
+	}
+	if strings.Join(opts.Files, ",") != "a.go,b.go" {
+		t.Errorf("Expected files=[a.go b.go], got %v", opts.Files)
+	}
+	if opts.Level == nil || *opts.Level != "debug" {
+		t.Errorf("Expected level='debug', got %v", opts.Level)
+	}
+	if opts.Retry != 3 {
+		t.Errorf("Expected retry=3, got %d", opts.Retry)
+	}
+	if opts.DB.Host != "db.local" {
+		t.Errorf("Expected db host='db.local', got '%s'", opts.DB.Host)
+	}
+	if opts.DB.Port == nil || *opts.DB.Port != 5432 {
+		t.Errorf("Expected db port=5432 from env, got %v", opts.DB.Port)
+	}
+	if opts.Cache == nil || opts.Cache.Host != "cache.local" {
+		t.Errorf("Expected cache host='cache.local', got %v", opts.Cache)
+	}
+	if len(remainArgs) != 1 || remainArgs[0] != "rest" {
+		t.Errorf("Expected remainArgs=['rest'], got %v", remainArgs)
+	}
+}
+
+func TestBindStruct_Usage(t *testing.T) {
+	var opts bindDBOptions
+	usage := BindStruct(&opts).Name("db").Usage()
+	expect := `Usage: db [OPTIONS]
+
+Options:
+  --host STRING  database host (default: localhost)
+  --port INT     (env: $TEST_DB_PORT)
+`
+	if usage != expect {
+		t.Errorf("Usage() mismatch, expect:\n%s\ngot:\n%s", expect, usage)
+	}
+}
+
+func TestBindStruct_Errors(t *testing.T) {
+	var notPtr bindOptions
+	_, err := BindStruct(notPtr).Parse(nil)
+	if err == nil || !strings.Contains(err.Error(), "expect pointer to struct") {
+		t.Errorf("Expected pointer error, got: %v", err)
+	}
+
+	var unsupported struct {
+		Ch chan int `flag:"--ch"`
+	}
+	_, err = BindStruct(&unsupported).Parse(nil)
+	if err == nil || !strings.Contains(err.Error(), "field Ch: unsupported type chan int") {
+		t.Errorf("Expected unsupported type error, got: %v", err)
+	}
+}
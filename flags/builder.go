@@ -3,18 +3,37 @@ package flags
 import (
 	"errors"
 	"fmt"
-	"os"
-	"reflect"
-	"strconv"
+	"io"
 	"strings"
-	"time"
 )
 
 // Builder represents a fluent flag parser builder
 type Builder struct {
 	flagSpecs      []FlagSpec
+	argSpecs       []ArgSpec
 	helpNoExit     bool
 	stopOnFirstArg bool
+	posix          bool
+	responseFiles  bool
+
+	// unknown flags kept in the remaining args, see Passthrough
+	passthrough       bool
+	passthroughValues []string
+
+	// subcommand tree, see Command
+	name        string
+	parent      *Builder
+	commands    []*Builder
+	handler     func(args []string) error
+	description string
+	groups      []flagGroup // constraints between flags
+
+	err error // configuration error reported by Parse
+
+	// output and exit, configured on the root, see Stdout
+	stdout io.Writer
+	stderr io.Writer
+	exit   func(code int)
 }
 
 // FlagSpec represents a single flag specification
@@ -22,8 +41,28 @@ type FlagSpec struct {
 	Names    []string    // flag names like ["-v", "--verbose"]
 	Target   interface{} // pointer to the target variable
 	Type     FlagType    // type of the flag
+	Value    Value       // parses values into Target
 	HelpText string
 	HelpFunc func()
+
+	Description string   // one line description shown in the generated help
+	Placeholder string   // value name shown in the generated help, like FILE
+	Default     string   // default value applied when the flag is absent
+	EnvVars     []string // environment variables consulted when the flag is absent
+	Separator   string   // splits each value of slice and map flags, see Split
+	Required    bool     // whether the flag must be set, see Required
+	Validators  []Validator
+
+	CompleteFunc  func(prefix string) []string // candidate values for shell completion
+	CompleteFiles bool                         // whether the value is a file path
+
+	Hidden          bool     // excluded from help, docs and completion
+	Deprecated      string   // warning printed when the flag is used
+	DeprecatedNames []string // old names accepted with a warning
+	Removed         string   // migration message of a removed flag, see Removed
+
+	help   bool // whether this is a help flag
+	config bool // whether this names a config file, see ConfigFile
 }
 
 // FlagType represents the type of flag
@@ -35,6 +74,13 @@ const (
 	FlagTypeInt
 	FlagTypeStringSlice
 	FlagTypeBool
+	FlagTypeValue // custom Value added by Var
+	FlagTypeCount
+	FlagTypeFloat
+	FlagTypeIntSlice
+	FlagTypeStringMap
+	FlagTypeTime
+	FlagTypeEnum
 )
 
 var ErrHelp = errors.New("help")
@@ -66,7 +112,8 @@ func StringSlice(names string, target interface{}) *Builder {
 	return (&Builder{}).StringSlice(names, target)
 }
 
-// Help creates a new builder and adds a help flag with help text
+// Help creates a new builder and adds a help flag with help text,
+// an empty helpText means the help is generated by Usage
 func Help(names string, helpText string) *Builder {
 	return (&Builder{}).Help(names, helpText)
 }
@@ -78,65 +125,45 @@ func HelpFunc(names string, helpFunc func()) *Builder {
 
 // String adds a string flag to the builder
 func (b *Builder) String(names string, target interface{}) *Builder {
-	flagNames := parseNames(names)
-	b.flagSpecs = append(b.flagSpecs, FlagSpec{
-		Names:  flagNames,
-		Target: target,
-		Type:   FlagTypeString,
-	})
+	b.addFlag(parseNames(names), FlagTypeString, target)
 	return b
 }
 
 func (b *Builder) Bool(names string, target interface{}) *Builder {
-	flagNames := parseNames(names)
-	b.flagSpecs = append(b.flagSpecs, FlagSpec{
-		Names:  flagNames,
-		Target: target,
-		Type:   FlagTypeBool,
-	})
+	b.addFlag(parseNames(names), FlagTypeBool, target)
 	return b
 }
 
 // Duration adds a duration flag to the builder
 func (b *Builder) Duration(names string, target interface{}) *Builder {
-	flagNames := parseNames(names)
-	b.flagSpecs = append(b.flagSpecs, FlagSpec{
-		Names:  flagNames,
-		Target: target,
-		Type:   FlagTypeDuration,
-	})
+	b.addFlag(parseNames(names), FlagTypeDuration, target)
 	return b
 }
 
 // Int adds an integer flag to the builder
 func (b *Builder) Int(names string, target interface{}) *Builder {
-	flagNames := parseNames(names)
-	b.flagSpecs = append(b.flagSpecs, FlagSpec{
-		Names:  flagNames,
-		Target: target,
-		Type:   FlagTypeInt,
-	})
+	b.addFlag(parseNames(names), FlagTypeInt, target)
 	return b
 }
 
 // StringSlice adds a string slice flag to the builder
 func (b *Builder) StringSlice(names string, target interface{}) *Builder {
-	flagNames := parseNames(names)
-	b.flagSpecs = append(b.flagSpecs, FlagSpec{
-		Names:  flagNames,
-		Target: target,
-		Type:   FlagTypeStringSlice,
-	})
+	b.addFlag(parseNames(names), FlagTypeStringSlice, target)
 	return b
 }
 
-// Help adds a help flag to the builder with help text
+// Help adds a help flag to the builder with help text.
+// If helpText is empty, the help is generated from the
+// registered flags, see Usage.
 func (b *Builder) Help(names string, helpText string) *Builder {
 	flagNames := parseNames(names)
 	b.flagSpecs = append(b.flagSpecs, FlagSpec{
 		Names:    flagNames,
 		Type:     FlagTypeBool,
 		HelpText: helpText,
+
+		Description: "show help message",
+		help:        true,
 	})
 	return b
 }
@@ -148,6 +175,9 @@ func (b *Builder) HelpFunc(names string, helpFunc func()) *Builder {
 		Names:    flagNames,
 		Type:     FlagTypeBool,
 		HelpFunc: helpFunc,
+
+		Description: "show help message",
+		help:        true,
 	})
 	return b
 }
@@ -163,239 +193,299 @@ func (b *Builder) StopOnFirstArg() *Builder {
 	return b
 }
 
-// Parse parses the arguments using the configured flags
+// Parse parses the arguments using the configured flags.
+//
+// When subcommands are registered via Command, the first
+// non-flag argument selects a subcommand, and parsing continues
+// with that subcommand's flags plus all flags inherited from its
+// ancestors. If the selected command has a handler, it is invoked
+// with the remaining args and its error is returned.
 func (b *Builder) Parse(args []string) ([]string, error) {
+	res, err := b.ParseResult(args)
+	if res == nil {
+		return nil, err
+	}
+	return res.Args, err
+}
+
+// ParseResult is like Parse but returns a ParseResult telling
+// where each flag came from and whether help was requested.
+// The result is nil if the command line is invalid.
+func (b *Builder) ParseResult(args []string) (*ParseResult, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(args) > 0 && args[0] == completeCommand {
+		return nil, b.printCompletion(args[1:])
+	}
+	if b.responseFiles {
+		var err error
+		args, err = expandResponseFiles(args, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
 	var remainArgs []string
+	// positional args and their index in args, remainArgs
+	// also holds the unknown flags kept by Passthrough
+	var posArgs []string
+	var posIndexes []int
+	addPositional := func(j int) {
+		remainArgs = append(remainArgs, args[j])
+		posArgs = append(posArgs, args[j])
+		posIndexes = append(posIndexes, j)
+	}
 	n := len(args)
 
+	cur := b
+	state := newParseState()
 	for i := 0; i < n; i++ {
 		if args[i] == "--" {
-			remainArgs = append(remainArgs, args[i+1:]...)
+			// the wrapped command needs -- to tell its args from flags
+			if cur.passthroughEnabled() {
+				remainArgs = append(remainArgs, args[i])
+			}
+			for j := i + 1; j < n; j++ {
+				addPositional(j)
+			}
 			break
 		}
+		if b.posix && isShortCluster(args[i]) {
+			name, _, _ := cutFlagValue(args[i])
+			if cur.findFlagSpec(name) == nil {
+				helpSpec, err := b.parseShortCluster(cur, args, &i, state)
+				if err != nil {
+					return nil, err
+				}
+				if helpSpec != nil {
+					return state.result(cur, remainArgs, true), b.showHelp(cur, helpSpec)
+				}
+				continue
+			}
+		}
+		start := i
 		flag, getValue := parseIndex(args, &i)
 		if flag == "" {
-			if b.stopOnFirstArg {
-				remainArgs = append(remainArgs, args[i:]...)
+			if len(posArgs) == 0 && len(cur.commands) > 0 {
+				cmd := cur.findCommand(args[i])
+				if cmd != nil {
+					cur = cmd
+					continue
+				}
+				if cur.handler == nil {
+					return nil, unknownCommandError(cur, args[i], i)
+				}
+			}
+			if cur.stopOnFirstArg {
+				for j := i; j < n; j++ {
+					addPositional(j)
+				}
 				break
 			}
-			remainArgs = append(remainArgs, args[i])
+			addPositional(i)
 			continue
 		}
 
-		spec := b.findFlagSpec(flag)
+		spec := cur.findFlagSpec(flag)
+		negated := false
 		if spec == nil {
-			return nil, fmt.Errorf("unrecognized flag: %s", flag)
+			spec = cur.findNegatedSpec(flag)
+			negated = spec != nil
+		}
+		if spec == nil {
+			passthrough, takesValue := cur.passthroughFlag(flag)
+			if !passthrough {
+				return nil, unknownFlagError(cur, flag, start)
+			}
+			remainArgs = append(remainArgs, args[start])
+			if takesValue && !strings.Contains(args[start], "=") && start+1 < n {
+				i = start + 1
+				remainArgs = append(remainArgs, args[i])
+			}
+			continue
+		}
+		err := b.checkUsable(spec, flag, start, state)
+		if err != nil {
+			return nil, err
 		}
 
 		// Handle help flag
-		if spec.HelpFunc != nil || spec.HelpText != "" {
-			for _, name := range spec.Names {
-				if name == flag {
-					if spec.HelpFunc != nil {
-						spec.HelpFunc()
-					} else if spec.HelpText != "" {
-						txt := strings.TrimPrefix(spec.HelpText, "\n")
-						fmt.Print(txt)
-						if !strings.HasSuffix(txt, "\n") {
-							fmt.Println()
-						}
-					}
-					if !b.helpNoExit {
-						os.Exit(0)
-					}
-					return remainArgs, ErrHelp
-				}
-			}
+		if spec.help {
+			return state.result(cur, remainArgs, true), b.showHelp(cur, spec)
 		}
 
 		// Get the value for non-bool flags
-		value, hasValue := getValue(spec.Type == FlagTypeBool)
+		value, hasValue := getValue(spec.isBool())
 		if !hasValue {
-			return nil, fmt.Errorf("%s requires a value", flag)
+			return nil, &ParseError{Kind: KindMissingValue, Name: flag, Index: start}
+		}
+		if negated {
+			if value != "" {
+				return nil, &ParseError{Kind: KindInvalidValue, Name: flag, Index: start, Err: errNegatedValue}
+			}
+			value = "false"
 		}
 
 		// Set the value based on type
-		err := b.setValue(spec, value)
+		err = state.setFlag(spec, value, SourceCLI)
 		if err != nil {
-			return nil, fmt.Errorf("error setting value for %s: %v", flag, err)
+			return nil, &ParseError{Kind: KindInvalidValue, Name: flag, Index: start, Err: err}
 		}
 	}
 
-	return remainArgs, nil
-}
-
-// findFlagSpec finds the flag specification for a given flag name
-func (b *Builder) findFlagSpec(flagName string) *FlagSpec {
-	for i := range b.flagSpecs {
-		spec := &b.flagSpecs[i]
-		for _, name := range spec.Names {
-			if name == flagName {
-				return spec
-			}
-		}
+	err := cur.applyFallbacks(state)
+	if err != nil {
+		return nil, err
 	}
-	return nil
-}
-
-// setValue sets the value to the target based on the flag type
-func (b *Builder) setValue(spec *FlagSpec, value string) error {
-	target := spec.Target
-
-	switch spec.Type {
-	case FlagTypeBool:
-		return setBoolValue(target, value)
-	case FlagTypeString:
-		return setStringValue(target, value)
-	case FlagTypeDuration:
-		return setDurationValue(target, value)
-	case FlagTypeInt:
-		return setIntValue(target, value)
-	case FlagTypeStringSlice:
-		return setStringSliceValue(target, value)
-	default:
-		return fmt.Errorf("unsupported flag type")
+	err = cur.checkConstraints(state)
+	if err != nil {
+		return nil, err
 	}
-}
-
-func setBoolValue(target interface{}, value string) error {
-	v := reflect.ValueOf(target)
-	if v.Kind() != reflect.Ptr {
-		return fmt.Errorf("target must be a pointer")
+	err = cur.bindArgs(posArgs, posIndexes)
+	if err != nil {
+		return nil, err
 	}
 
-	elem := v.Elem()
-	boolVal := value == "" || value == "true"
-
-	if elem.Kind() == reflect.Ptr {
-		// Handle **bool
-		if elem.IsNil() {
-			elem.Set(reflect.New(elem.Type().Elem()))
-		}
-		elem.Elem().SetBool(boolVal)
-	} else if elem.Kind() == reflect.Bool {
-		// Handle *bool
-		elem.SetBool(boolVal)
-	} else {
-		return fmt.Errorf("target must be *bool or **bool, actual: %v", elem.Type())
+	res := state.result(cur, remainArgs, false)
+	if cur.handler != nil {
+		return res, cur.handler(remainArgs)
+	}
+	if len(cur.commands) > 0 && len(posArgs) == 0 {
+		return nil, &ParseError{Kind: KindMissingCommand, Name: cur.commandPath(), Index: -1}
 	}
 
-	return nil
+	return res, nil
 }
 
-// setStringValue sets a string value to a target (supports *string and **string)
-func setStringValue(target interface{}, value string) error {
-	v := reflect.ValueOf(target)
-	if v.Kind() != reflect.Ptr {
-		return fmt.Errorf("target must be a pointer")
+// showHelp prints the help of cmd and exits unless HelpNoExit is set
+func (b *Builder) showHelp(cmd *Builder, spec *FlagSpec) error {
+	if spec.HelpFunc != nil {
+		spec.HelpFunc()
+	} else {
+		txt := strings.TrimPrefix(spec.HelpText, "\n")
+		if txt == "" {
+			txt = cmd.Usage()
+		}
+		w := b.outWriter()
+		fmt.Fprint(w, txt)
+		if !strings.HasSuffix(txt, "\n") {
+			fmt.Fprintln(w)
+		}
 	}
+	b.exitNow(0)
+	return ErrHelp
+}
 
-	elem := v.Elem()
-	if elem.Kind() == reflect.Ptr {
-		// Handle **string
-		if elem.IsNil() {
-			elem.Set(reflect.New(elem.Type().Elem()))
+// findFlagSpec finds the flag specification for a given flag name,
+// falling back to flags inherited from parent commands
+func (b *Builder) findFlagSpec(flagName string) *FlagSpec {
+	for c := b; c != nil; c = c.parent {
+		for i := range c.flagSpecs {
+			spec := &c.flagSpecs[i]
+			for _, name := range spec.Names {
+				if name == flagName {
+					return spec
+				}
+			}
+			if containsString(spec.DeprecatedNames, flagName) {
+				return spec
+			}
 		}
-		elem.Elem().SetString(value)
-	} else if elem.Kind() == reflect.String {
-		// Handle *string
-		elem.SetString(value)
-	} else {
-		return fmt.Errorf("target must be *string or **string")
 	}
-
 	return nil
 }
 
-// setDurationValue sets a duration value to a target
-func setDurationValue(target interface{}, value string) error {
-	duration, err := time.ParseDuration(value)
-	if err != nil {
-		return err
-	}
+// negatedPrefix negates a bool flag, --no-color sets --color to false
+const negatedPrefix = "--no-"
 
-	v := reflect.ValueOf(target)
-	if v.Kind() != reflect.Ptr {
-		return fmt.Errorf("target must be a pointer")
-	}
+var errNegatedValue = errors.New("negated flag takes no value")
 
-	elem := v.Elem()
-	if elem.Kind() == reflect.Ptr {
-		// Handle **time.Duration
-		if elem.IsNil() {
-			elem.Set(reflect.New(elem.Type().Elem()))
-		}
-		elem.Elem().Set(reflect.ValueOf(duration))
-	} else if elem.Type() == reflect.TypeOf(time.Duration(0)) {
-		// Handle *time.Duration
-		elem.Set(reflect.ValueOf(duration))
-	} else {
-		return fmt.Errorf("target must be *time.Duration or **time.Duration")
+// findNegatedSpec finds the bool flag negated by flagName,
+// like --color for --no-color
+func (b *Builder) findNegatedSpec(flagName string) *FlagSpec {
+	if !strings.HasPrefix(flagName, negatedPrefix) {
+		return nil
 	}
-
-	return nil
+	spec := b.findFlagSpec("--" + strings.TrimPrefix(flagName, negatedPrefix))
+	if spec == nil || spec.Type != FlagTypeBool || spec.help {
+		return nil
+	}
+	return spec
 }
 
-// setIntValue sets an integer value to a target
-func setIntValue(target interface{}, value string) error {
-	intVal, err := strconv.Atoi(value)
+// addFlag adds a flag whose value is parsed into target
+// by the built-in Value of flagType
+func (b *Builder) addFlag(names []string, flagType FlagType, target interface{}) *FlagSpec {
+	value, err := newValue(flagType, target)
 	if err != nil {
-		return err
-	}
-
-	v := reflect.ValueOf(target)
-	if v.Kind() != reflect.Ptr {
-		return fmt.Errorf("target must be a pointer")
+		b.setErr(fmt.Errorf("%s: %v", strings.Join(names, ","), err))
 	}
+	b.flagSpecs = append(b.flagSpecs, FlagSpec{
+		Names:  names,
+		Target: target,
+		Type:   flagType,
+		Value:  value,
+	})
+	return &b.flagSpecs[len(b.flagSpecs)-1]
+}
 
-	elem := v.Elem()
+// parseState records the flags set during one Parse
+type parseState struct {
+	set     map[*FlagSpec]bool     // set from the command line or environment
+	values  map[*FlagSpec][]string // raw values, including defaults
+	sources map[*FlagSpec]Source
+	warned  map[string]bool // deprecated names already warned about
+}
 
-	setElem := elem
-	if elem.Kind() == reflect.Ptr {
-		// Handle **int or **int64
-		if elem.IsNil() {
-			elem.Set(reflect.New(elem.Type().Elem()))
-		}
-		setElem = elem.Elem()
+func newParseState() *parseState {
+	return &parseState{
+		set:     make(map[*FlagSpec]bool),
+		values:  make(map[*FlagSpec][]string),
+		sources: make(map[*FlagSpec]Source),
+		warned:  make(map[string]bool),
 	}
+}
 
-	elemType := setElem.Type()
-	switch elemType.Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		setElem.SetInt(int64(intVal))
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		setElem.SetUint(uint64(intVal))
-	default:
-		return fmt.Errorf("target must be *int or **int, actual: %v", elem.Type())
+// setFlag sets value to the flag and records it with its source,
+// values not from defaults count as given by the user
+func (s *parseState) setFlag(spec *FlagSpec, value string, source Source) error {
+	err := setValue(spec, value)
+	if err != nil {
+		return err
 	}
-
+	if source != SourceDefault {
+		s.set[spec] = true
+	}
+	s.values[spec] = append(s.values[spec], value)
+	s.sources[spec] = source
 	return nil
 }
 
-// setStringSliceValue sets a string slice value to a target
-func setStringSliceValue(target interface{}, value string) error {
-	v := reflect.ValueOf(target)
-	if v.Kind() != reflect.Ptr {
-		return fmt.Errorf("target must be a pointer")
+// result builds the ParseResult for the command cmd
+func (s *parseState) result(cmd *Builder, args []string, help bool) *ParseResult {
+	return &ParseResult{
+		Args:    args,
+		Command: cmd.commandPath(),
+		Help:    help,
+		cmd:     cmd,
+		sources: s.sources,
 	}
+}
 
-	elem := v.Elem()
-	if elem.Kind() == reflect.Ptr {
-		// Handle **[]string
-		if elem.IsNil() {
-			elem.Set(reflect.New(elem.Type().Elem()))
+// setValue sets the value to the target of the flag
+func setValue(spec *FlagSpec, value string) error {
+	if spec.Value == nil {
+		return fmt.Errorf("flag has no value")
+	}
+	if spec.Separator == "" {
+		return spec.Value.Set(value)
+	}
+	for _, part := range strings.Split(value, spec.Separator) {
+		err := spec.Value.Set(part)
+		if err != nil {
+			return err
 		}
-		slice := elem.Elem()
-		newVal := reflect.Append(slice, reflect.ValueOf(value))
-		slice.Set(newVal)
-	} else if elem.Kind() == reflect.Slice && elem.Type().Elem().Kind() == reflect.String {
-		// Handle *[]string
-		newVal := reflect.Append(elem, reflect.ValueOf(value))
-		elem.Set(newVal)
-	} else {
-		return fmt.Errorf("target must be *[]string or **[]string")
 	}
-
 	return nil
 }
 
@@ -575,6 +575,86 @@ func TestBuilder_BoolDoublePointer(t *testing.T) {
 	}
 }
 
+func TestBuilder_BoolSpellings(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected bool
+	}{
+		{"--color", true},
+		{"--color=1", true},
+		{"--color=T", true},
+		{"--color=false", false},
+		{"--color=0", false},
+		{"--no-color", false},
+	}
+	for _, tt := range tests {
+		color := !tt.expected
+		_, err := Bool("--color", &color).Parse([]string{tt.arg})
+		if err != nil {
+			t.Errorf("Parse(%s) failed: %v", tt.arg, err)
+			continue
+		}
+		if color != tt.expected {
+			t.Errorf("Parse(%s): expected color=%v, got %v", tt.arg, tt.expected, color)
+		}
+	}
+
+	var color bool
+	_, err := Bool("--color", &color).Parse([]string{"--color=yes"})
+	if err == nil || !strings.Contains(err.Error(), `invalid boolean "yes"`) {
+		t.Errorf("Expected invalid boolean error, got: %v", err)
+	}
+}
+
+func TestBuilder_BoolNegation(t *testing.T) {
+	var color bool
+	var noCache bool
+	var name string
+	b := Bool("--color", &color).Default("true").
+		Bool("--no-cache", &noCache).
+		String("--name", &name)
+
+	res, err := b.ParseResult([]string{"--no-color", "--no-cache"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if color || !noCache {
+		t.Errorf("Expected color=false and noCache=true, got %v %v", color, noCache)
+	}
+	if res.Source("--no-color") != SourceCLI || res.Source("--color") != SourceCLI {
+		t.Errorf("Expected --color from cli")
+	}
+
+	_, err = b.Parse([]string{"--no-color=true"})
+	if err == nil || !strings.Contains(err.Error(), "negated flag takes no value") {
+		t.Errorf("Expected negated value error, got: %v", err)
+	}
+	_, err = b.Parse([]string{"--no-name"})
+	if err == nil || !strings.Contains(err.Error(), "unrecognized flag: --no-name") {
+		t.Errorf("Expected only bool flags to be negatable, got: %v", err)
+	}
+}
+
+func TestBuilder_BoolTriState(t *testing.T) {
+	parse := func(args ...string) *bool {
+		var color *bool
+		_, err := Bool("--color", &color).Parse(args)
+		if err != nil {
+			t.Fatalf("Parse(%v) failed: %v", args, err)
+		}
+		return color
+	}
+	if color := parse(); color != nil {
+		t.Errorf("Expected unset color to be nil, got %v", *color)
+	}
+	if color := parse("--color"); color == nil || !*color {
+		t.Errorf("Expected color=true")
+	}
+	if color := parse("--no-color"); color == nil || *color {
+		t.Errorf("Expected color=false")
+	}
+}
+
 func TestBuilder_Int64(t *testing.T) {
 	var count int64
 	args := []string{"--count", "9223372036854775807"}
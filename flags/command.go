@@ -0,0 +1,77 @@
+package flags
+
+import "strings"
+
+// Command creates a new builder and adds a subcommand
+func Command(name string, fn func(c *Builder)) *Builder {
+	return (&Builder{}).Command(name, fn)
+}
+
+// Command adds a subcommand to the builder. fn is called immediately
+// with the subcommand's builder to register its flags, help text,
+// nested subcommands and handler.
+//
+// Flags registered on b are inherited by the subcommand, so global
+// flags may appear both before and after the subcommand name:
+//
+//	flags.Bool("-v,--verbose", &verbose).
+//		Command("gen", func(c *flags.Builder) {
+//			c.Command("api", func(c *flags.Builder) {
+//				c.String("--pkg", &pkg).
+//					Help("-h,--help", genAPIHelp).
+//					Handle(genAPI)
+//			})
+//		}).
+//		Parse([]string{"gen", "api", "--pkg", "x"})
+func (b *Builder) Command(name string, fn func(c *Builder)) *Builder {
+	cmd := &Builder{
+		name:   name,
+		parent: b,
+	}
+	if fn != nil {
+		fn(cmd)
+	}
+	b.commands = append(b.commands, cmd)
+	return b
+}
+
+// Handle sets the handler invoked by Parse when this builder is
+// the selected command. The handler receives the remaining args.
+func (b *Builder) Handle(handler func(args []string) error) *Builder {
+	b.handler = handler
+	return b
+}
+
+// findCommand finds a direct subcommand by name
+func (b *Builder) findCommand(name string) *Builder {
+	for _, cmd := range b.commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// root returns the top level builder
+func (b *Builder) root() *Builder {
+	root := b
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root
+}
+
+// commandPath returns the space separated names from the root
+// command down to b, skipping an unnamed root
+func (b *Builder) commandPath() string {
+	var names []string
+	for c := b; c != nil; c = c.parent {
+		if c.name != "" {
+			names = append(names, c.name)
+		}
+	}
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,128 @@
+package flags
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommand_Dispatch(t *testing.T) {
+	var verbose bool
+	var pkg string
+	var called []string
+	var handlerArgs []string
+
+	b := Bool("-v,--verbose", &verbose).
+		Command("gen", func(c *Builder) {
+			c.Command("api", func(c *Builder) {
+				c.String("--pkg", &pkg).Handle(func(args []string) error {
+					called = append(called, "gen api")
+					handlerArgs = args
+					return nil
+				})
+			})
+		}).
+		Command("clean", func(c *Builder) {
+			c.Handle(func(args []string) error {
+				called = append(called, "clean")
+				return nil
+			})
+		})
+
+	remainArgs, err := b.Parse([]string{"gen", "api", "--pkg", "x", "-v", "file.go"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if strings.Join(called, ",") != "gen api" {
+		t.Errorf("Expected gen api to be called, got %v", called)
+	}
+	if pkg != "x" {
+		t.Errorf("Expected pkg='x', got '%s'", pkg)
+	}
+	if !verbose {
+		t.Errorf("Expected inherited verbose=true")
+	}
+	if len(handlerArgs) != 1 || handlerArgs[0] != "file.go" {
+		t.Errorf("Expected handler args=['file.go'], got %v", handlerArgs)
+	}
+	if len(remainArgs) != 1 || remainArgs[0] != "file.go" {
+		t.Errorf("Expected remainArgs=['file.go'], got %v", remainArgs)
+	}
+}
+
+func TestCommand_GlobalFlagBeforeCommand(t *testing.T) {
+	var verbose bool
+	var called bool
+	_, err := Bool("-v,--verbose", &verbose).
+		Command("clean", func(c *Builder) {
+			c.Handle(func(args []string) error {
+				called = true
+				return nil
+			})
+		}).
+		Parse([]string{"-v", "clean"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !verbose || !called {
+		t.Errorf("Expected verbose and clean called, got verbose=%v called=%v", verbose, called)
+	}
+}
+
+func TestCommand_FlagNotInheritedBySibling(t *testing.T) {
+	var pkg string
+	_, err := Command("gen", func(c *Builder) {
+		c.String("--pkg", &pkg).Handle(func(args []string) error { return nil })
+	}).
+		Command("clean", func(c *Builder) {
+			c.Handle(func(args []string) error { return nil })
+		}).
+		Parse([]string{"clean", "--pkg", "x"})
+	if err == nil || !strings.Contains(err.Error(), "unrecognized flag: --pkg") {
+		t.Errorf("Expected unrecognized flag error, got: %v", err)
+	}
+}
+
+func TestCommand_Unrecognized(t *testing.T) {
+	_, err := Command("gen", func(c *Builder) {}).Parse([]string{"build"})
+	if err == nil || !strings.Contains(err.Error(), "unrecognized command: build") {
+		t.Errorf("Expected unrecognized command error, got: %v", err)
+	}
+}
+
+func TestCommand_RequiresSubcommand(t *testing.T) {
+	b := Command("gen", func(c *Builder) {
+		c.Command("api", func(c *Builder) {})
+	})
+	_, err := b.Parse([]string{"gen"})
+	if err == nil || !strings.Contains(err.Error(), "gen requires a subcommand") {
+		t.Errorf("Expected missing subcommand error, got: %v", err)
+	}
+}
+
+func TestCommand_HelpPerCommand(t *testing.T) {
+	var rootHelp, genHelp bool
+	b := HelpFunc("-h,--help", func() { rootHelp = true }).
+		Command("gen", func(c *Builder) {
+			c.HelpFunc("-h,--help", func() { genHelp = true })
+		}).
+		HelpNoExit()
+
+	_, err := b.Parse([]string{"gen", "--help"})
+	if err != ErrHelp {
+		t.Errorf("expected ErrHelp, got %v", err)
+	}
+	if rootHelp || !genHelp {
+		t.Errorf("Expected only gen help, got root=%v gen=%v", rootHelp, genHelp)
+	}
+}
+
+func TestCommand_HandlerError(t *testing.T) {
+	_, err := Command("fail", func(c *Builder) {
+		c.Handle(func(args []string) error {
+			return ErrHelp
+		})
+	}).Parse([]string{"fail"})
+	if err != ErrHelp {
+		t.Errorf("Expected handler error to be returned, got %v", err)
+	}
+}
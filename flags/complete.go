@@ -0,0 +1,339 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrComplete is returned by Parse after answering a
+// completion request when HelpNoExit is set
+var ErrComplete = errors.New("complete")
+
+// completeCommand is the hidden command the generated
+// completion scripts call back into:
+//
+//	prog __complete [WORDS...] CURRENT
+//
+// It prints one candidate per line, optionally followed by a tab
+// and a description, and ends with a directive line telling the
+// shell how to proceed:
+//
+//	:default          fall back to the shell's default completion
+//	:files            complete file paths
+//	:nofiles [HINT]   no further completion, HINT describes the value
+const completeCommand = "__complete"
+
+const (
+	directiveDefault = "default"
+	directiveFiles   = "files"
+	directiveNoFiles = "nofiles"
+)
+
+// Complete sets a function providing candidate values of the last
+// added flag, candidates not starting with prefix are dropped
+func (b *Builder) Complete(fn func(prefix string) []string) *Builder {
+	b.lastSpec("Complete").CompleteFunc = fn
+	return b
+}
+
+// CompleteFiles marks the value of the last added flag as a file path
+func (b *Builder) CompleteFiles() *Builder {
+	b.lastSpec("CompleteFiles").CompleteFiles = true
+	return b
+}
+
+// CompletionCommand adds a subcommand printing the completion
+// script for the shell given as its argument: bash, zsh or fish
+//
+//	source <(myapp completion bash)
+func (b *Builder) CompletionCommand(name string) *Builder {
+	return b.Command(name, func(c *Builder) {
+		c.Description("print shell completion script for bash, zsh or fish").
+			Handle(func(args []string) error {
+				shell, err := OnlyArg(args)
+				if err != nil {
+					return err
+				}
+				script, err := b.Completion(shell)
+				if err != nil {
+					return err
+				}
+				fmt.Fprint(b.outWriter(), script)
+				return nil
+			})
+	})
+}
+
+// Completion returns the completion script for shell, one of
+// bash, zsh and fish. The script completes flags, subcommands
+// and values by calling the program's hidden __complete command.
+func (b *Builder) Completion(shell string) (string, error) {
+	prog := b.root().programPath()
+	fn := "_" + nonIdentChars.ReplaceAllString(prog, "_") + "_complete"
+
+	var tpl string
+	switch shell {
+	case "bash":
+		tpl = bashCompletion
+	case "zsh":
+		tpl = zshCompletion
+	case "fish":
+		tpl = fishCompletion
+	default:
+		return "", fmt.Errorf("unsupported shell: %s, expect bash, zsh or fish", shell)
+	}
+	return strings.NewReplacer("{{PROG}}", prog, "{{FUNC}}", fn).Replace(tpl), nil
+}
+
+var nonIdentChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+type completion struct {
+	value string
+	desc  string
+}
+
+// printCompletion answers a __complete request
+func (b *Builder) printCompletion(words []string) error {
+	candidates, directive := b.complete(words)
+	w := b.outWriter()
+	for _, c := range candidates {
+		if c.desc == "" {
+			fmt.Fprintln(w, c.value)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\n", c.value, c.desc)
+	}
+	fmt.Fprintf(w, ":%s\n", directive)
+	b.exitNow(0)
+	return ErrComplete
+}
+
+// complete computes the candidates for the last word, the
+// preceding words are walked like Parse to find the command
+// and whether a flag value is expected
+func (b *Builder) complete(words []string) ([]completion, string) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	words, split := joinFlagValues(words)
+	n := len(words)
+	last := words[n-1]
+
+	cur := b
+	var positional bool
+	var dashdash bool
+	var valueSpec *FlagSpec
+	for i := 0; i < n-1; i++ {
+		w := words[i]
+		if dashdash {
+			continue
+		}
+		if w == "--" {
+			dashdash = true
+			continue
+		}
+		if strings.HasPrefix(w, "-") && w != "-" {
+			name, _, hasValue := cutFlagValue(w)
+			spec := cur.findFlagSpec(name)
+			if spec != nil && !spec.isBool() && !hasValue {
+				if i == n-2 {
+					valueSpec = spec
+				}
+				i++
+			}
+			continue
+		}
+		if !positional && len(cur.commands) > 0 {
+			if cmd := cur.findCommand(w); cmd != nil {
+				cur = cmd
+				continue
+			}
+		}
+		positional = true
+	}
+
+	if valueSpec != nil {
+		return valueCompletions(valueSpec, last, "")
+	}
+	if dashdash {
+		return nil, directiveDefault
+	}
+
+	if strings.HasPrefix(last, "-") {
+		if name, value, ok := cutFlagValue(last); ok {
+			spec := cur.findFlagSpec(name)
+			if spec == nil || spec.isBool() {
+				return nil, directiveNoFiles
+			}
+			// bash only replaces the text after the =
+			if split {
+				return valueCompletions(spec, value, "")
+			}
+			return valueCompletions(spec, value, name+"=")
+		}
+		var candidates []completion
+		for c := cur; c != nil; c = c.parent {
+			for i := range c.flagSpecs {
+				spec := &c.flagSpecs[i]
+				if spec.hidden() {
+					continue
+				}
+				for _, name := range spec.Names {
+					if strings.HasPrefix(name, last) && cur.findFlagSpec(name) == spec {
+						candidates = append(candidates, completion{value: name, desc: spec.Description})
+					}
+				}
+			}
+		}
+		return candidates, directiveNoFiles
+	}
+
+	if !positional && len(cur.commands) > 0 {
+		var candidates []completion
+		for _, cmd := range cur.commands {
+			if strings.HasPrefix(cmd.name, last) {
+				candidates = append(candidates, completion{value: cmd.name, desc: firstLine(cmd.description)})
+			}
+		}
+		if cur.handler == nil {
+			return candidates, directiveNoFiles
+		}
+		return candidates, directiveDefault
+	}
+	return nil, directiveDefault
+}
+
+func valueCompletions(spec *FlagSpec, prefix string, insertPrefix string) ([]completion, string) {
+	var values []string
+	if spec.CompleteFunc != nil {
+		values = spec.CompleteFunc(prefix)
+	} else if cv, ok := spec.Value.(choicesValue); ok {
+		values = cv.Choices()
+	}
+	var candidates []completion
+	if len(values) > 0 {
+		for _, v := range values {
+			if strings.HasPrefix(v, prefix) {
+				candidates = append(candidates, completion{value: insertPrefix + v})
+			}
+		}
+	}
+	if spec.CompleteFiles {
+		return candidates, directiveFiles
+	}
+	if len(candidates) == 0 {
+		return nil, directiveNoFiles + " " + flagPlaceholder(spec)
+	}
+	return candidates, directiveNoFiles
+}
+
+// joinFlagValues joins --flag=value split by bash into --flag, =
+// and value, = being in COMP_WORDBREAKS. It reports whether the
+// last word was joined.
+func joinFlagValues(words []string) ([]string, bool) {
+	var joined []string
+	var split bool
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		k := len(joined) - 1
+		if w == "=" && k >= 0 && strings.HasPrefix(joined[k], "-") && !strings.Contains(joined[k], "=") {
+			joined[k] += w
+			if i+1 < len(words) {
+				i++
+				joined[k] += words[i]
+			}
+			split = i == len(words)-1
+			continue
+		}
+		joined = append(joined, w)
+		split = false
+	}
+	return joined, split
+}
+
+// cutFlagValue splits --flag=value
+func cutFlagValue(arg string) (string, string, bool) {
+	idx := strings.Index(arg[1:], "=")
+	if idx < 0 {
+		return arg, "", false
+	}
+	return arg[:idx+1], arg[idx+2:], true
+}
+
+const bashCompletion = `# bash completion for {{PROG}}
+# usage: source <({{PROG}} completion bash)
+{{FUNC}}() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    [[ "$cur" == "=" ]] && cur=""
+    local out directive line
+    out=$({{PROG}} __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null) || return
+    directive=${out##*$'\n'}
+    out=${out%"$directive"}
+    COMPREPLY=()
+    while IFS= read -r line; do
+        [[ -n "$line" ]] && COMPREPLY+=("${line%%$'\t'*}")
+    done <<< "$out"
+    case "$directive" in
+        :files*)
+            while IFS= read -r line; do
+                COMPREPLY+=("$line")
+            done < <(compgen -f -- "$cur")
+            ;;
+        :nofiles*)
+            compopt +o default 2>/dev/null
+            ;;
+    esac
+}
+complete -o default -F {{FUNC}} {{PROG}}
+`
+
+const zshCompletion = `#compdef {{PROG}}
+# zsh completion for {{PROG}}
+# usage: source <({{PROG}} completion zsh)
+{{FUNC}}() {
+    local -a lines completions
+    local directive line
+    lines=("${(@f)$({{PROG}} __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    directive=${lines[-1]}
+    for line in "${(@)lines[1,-2]}"; do
+        [[ -z "$line" ]] && continue
+        completions+=("${${line%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+    done
+    if (( ${#completions} )); then
+        _describe -t values 'values' completions
+    fi
+    case "$directive" in
+        :files*)
+            _files
+            ;;
+        ":nofiles "*)
+            (( ${#completions} )) || _message "${directive#:nofiles }"
+            ;;
+        :default)
+            (( ${#completions} )) || _files
+            ;;
+    esac
+}
+compdef {{FUNC}} {{PROG}}
+`
+
+const fishCompletion = `# fish completion for {{PROG}}
+# usage: {{PROG}} completion fish | source
+function {{FUNC}}
+    set -l tokens (commandline -opc) (commandline -ct)
+    set -l out ({{PROG}} __complete $tokens[2..-1] 2>/dev/null)
+    set -l directive $out[-1]
+    set -e out[-1]
+    for line in $out
+        echo $line
+    end
+    if string match -q ':files*' -- $directive
+        __fish_complete_path (commandline -ct)
+    else if test "$directive" = ':default'; and test (count $out) -eq 0
+        __fish_complete_path (commandline -ct)
+    end
+end
+complete -c {{PROG}} -f -a '({{FUNC}})'
+`
@@ -0,0 +1,89 @@
+package flags
+
+import (
+	"strings"
+	"testing"
+)
+
+func newCompleteTestBuilder() *Builder {
+	var verbose bool
+	var file, level, pkg string
+	levels := func(prefix string) []string {
+		return []string{"debug", "info", "error"}
+	}
+	return Bool("-v,--verbose", &verbose).Desc("enable verbose output").
+		String("--file", &file).Placeholder("FILE").CompleteFiles().
+		String("--level", &level).Complete(levels).
+		Name("mytool").
+		Command("gen", func(c *Builder) {
+			c.Description("generate code").
+				String("--pkg", &pkg).
+				Handle(func(args []string) error { return nil })
+		}).
+		Command("clean", func(c *Builder) {})
+}
+
+func formatCompletions(candidates []completion, directive string) string {
+	var lines []string
+	for _, c := range candidates {
+		lines = append(lines, c.value)
+	}
+	lines = append(lines, ":"+directive)
+	return strings.Join(lines, " ")
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		words  []string
+		expect string
+	}{
+		{nil, "gen clean :nofiles"},
+		{[]string{"g"}, "gen :nofiles"},
+		{[]string{"--v"}, "--verbose :nofiles"},
+		{[]string{"-"}, "-v --verbose --file --level :nofiles"},
+		{[]string{"gen", "--"}, "--pkg --verbose --file --level :nofiles"},
+		{[]string{"--level", ""}, "debug info error :nofiles"},
+		{[]string{"--level", "d"}, "debug :nofiles"},
+		{[]string{"--level=e"}, "--level=error :nofiles"},
+		// bash splits --level=e at the =
+		{[]string{"--level", "=", "e"}, "error :nofiles"},
+		{[]string{"--level", "="}, "debug info error :nofiles"},
+		{[]string{"--level", "=", "info", "c"}, "clean :nofiles"},
+		{[]string{"--file", "=", ""}, ":files"},
+		{[]string{"--file", ""}, ":files"},
+		{[]string{"gen", "--pkg", ""}, ":nofiles STRING"},
+		{[]string{"gen", ""}, ":default"},
+		{[]string{"-v", "--level", "info", "c"}, "clean :nofiles"},
+	}
+	b := newCompleteTestBuilder()
+	for _, tt := range tests {
+		got := formatCompletions(b.complete(tt.words))
+		if got != tt.expect {
+			t.Errorf("complete(%q): expect %q, got %q", tt.words, tt.expect, got)
+		}
+	}
+}
+
+func TestCompletion_Scripts(t *testing.T) {
+	b := newCompleteTestBuilder()
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		script, err := b.Completion(shell)
+		if err != nil {
+			t.Fatalf("Completion(%s): %v", shell, err)
+		}
+		if !strings.Contains(script, "_mytool_complete") || !strings.Contains(script, "mytool __complete") {
+			t.Errorf("Completion(%s) does not call back into __complete:\n%s", shell, script)
+		}
+	}
+	_, err := b.Completion("powershell")
+	if err == nil || !strings.Contains(err.Error(), "unsupported shell: powershell") {
+		t.Errorf("Expected unsupported shell error, got: %v", err)
+	}
+}
+
+func TestComplete_Parse(t *testing.T) {
+	_, err := newCompleteTestBuilder().HelpNoExit().Parse([]string{"__complete", "--le"})
+	if err != ErrComplete {
+		t.Errorf("Expected ErrComplete, got %v", err)
+	}
+}
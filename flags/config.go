@@ -0,0 +1,227 @@
+package flags
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxResponseFileDepth limits nested @file expansion
+const maxResponseFileDepth = 10
+
+// ResponseFiles makes Parse expand arguments like @args.txt into
+// the lines of the file, one argument per line. Lines are trimmed,
+// blank lines and lines starting with # are skipped, and a line
+// can be double quoted with Go escapes or single quoted to keep
+// spaces. Arguments after -- are not expanded.
+func (b *Builder) ResponseFiles() *Builder {
+	b.responseFiles = true
+	return b
+}
+
+// ConfigFile adds a string flag naming a JSON file of flag values,
+// whose keys are the long flag names without dashes:
+//
+//	{"timeout": "30s", "file": ["a.go", "b.go"], "verbose": true}
+//
+// Values are layered as defaults < config file < env < command line.
+// The flag can have its own environment variables and default,
+// a missing file is ignored only when named by the default.
+//
+//	flags.ConfigFile("--config").Default("app.json")
+func ConfigFile(names string) *Builder {
+	return (&Builder{}).ConfigFile(names)
+}
+
+// ConfigFile adds a flag naming a JSON file of flag values, see ConfigFile
+func (b *Builder) ConfigFile(names string) *Builder {
+	var path string
+	spec := b.addFlag(parseNames(names), FlagTypeString, &path)
+	spec.Placeholder = "FILE"
+	spec.Description = "load flag values from a JSON file"
+	spec.CompleteFiles = true
+	spec.config = true
+	return b
+}
+
+// expandResponseFiles replaces @file arguments with the file contents
+func expandResponseFiles(args []string, depth int) ([]string, error) {
+	var expanded []string
+	for i, arg := range args {
+		if arg == "--" {
+			return append(expanded, args[i:]...), nil
+		}
+		if len(arg) < 2 || arg[0] != '@' {
+			expanded = append(expanded, arg)
+			continue
+		}
+		if depth >= maxResponseFileDepth {
+			return nil, fmt.Errorf("response file %s: nested too deep", arg[1:])
+		}
+		fileArgs, err := readResponseFile(arg[1:])
+		if err != nil {
+			return nil, err
+		}
+		fileArgs, err = expandResponseFiles(fileArgs, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, fileArgs...)
+	}
+	return expanded, nil
+}
+
+func readResponseFile(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("response file: %w", err)
+	}
+	var args []string
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		switch {
+		case len(line) >= 2 && line[0] == '"' && line[len(line)-1] == '"':
+			s, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, fmt.Errorf("response file %s:%d: invalid quoted argument %s", file, i+1, line)
+			}
+			line = s
+		case len(line) >= 2 && line[0] == '\'' && line[len(line)-1] == '\'':
+			line = line[1 : len(line)-1]
+		}
+		args = append(args, line)
+	}
+	return args, nil
+}
+
+// loadConfig reads the config file named by spec, returning
+// the file name and the values by key, or nil if there is none
+func (s *parseState) loadConfig(spec *FlagSpec) (string, map[string][]string, error) {
+	values := s.values[spec]
+	if len(values) == 0 || values[len(values)-1] == "" {
+		return "", nil, nil
+	}
+	file := values[len(values)-1]
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) && s.sources[spec] == SourceDefault {
+			return "", nil, nil
+		}
+		return "", nil, fmt.Errorf("config file: %w", err)
+	}
+	var raw map[string]json.RawMessage
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err = dec.Decode(&raw)
+	if err != nil {
+		return "", nil, fmt.Errorf("config file %s: %v", file, err)
+	}
+	config := make(map[string][]string, len(raw))
+	for key, msg := range raw {
+		values, err := configValues(msg)
+		if err != nil {
+			return "", nil, fmt.Errorf("config file %s: %s: %v", file, key, err)
+		}
+		config[key] = values
+	}
+	return file, config, nil
+}
+
+// configValues converts a JSON value into flag values: arrays give
+// one value per element and objects one key=value per entry
+func configValues(msg json.RawMessage) ([]string, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.UseNumber()
+	err := dec.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, e := range v {
+			s, err := configScalar(e)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]string, 0, len(keys))
+		for _, k := range keys {
+			s, err := configScalar(v[k])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, k+"="+s)
+		}
+		return values, nil
+	default:
+		s, err := configScalar(v)
+		if err != nil {
+			return nil, err
+		}
+		return []string{s}, nil
+	}
+}
+
+func configScalar(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("expect string, number or bool, actual: %T", v)
+	}
+}
+
+// configKeys returns the keys of the flag in config files
+func configKeys(spec *FlagSpec) []string {
+	if spec.Removed != "" {
+		return nil
+	}
+	var keys []string
+	for _, name := range spec.Names {
+		if strings.HasPrefix(name, "--") {
+			keys = append(keys, name[2:])
+		}
+	}
+	return keys
+}
+
+// hasConfigKey reports whether any flag in the command tree of b
+// is configured by key
+func (b *Builder) hasConfigKey(key string) bool {
+	for i := range b.flagSpecs {
+		for _, k := range configKeys(&b.flagSpecs[i]) {
+			if k == key {
+				return true
+			}
+		}
+	}
+	for _, cmd := range b.commands {
+		if cmd.hasConfigKey(key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package flags
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(file, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestResponseFiles(t *testing.T) {
+	inner := writeTestFile(t, "inner.txt", "--name\n'  spaced  '\n")
+	file := writeTestFile(t, "args.txt", `
+# flags for the generator
+--timeout
+10s
+"--file=a b.go"
+@`+inner+`
+pkg
+`)
+	var timeout time.Duration
+	var files []string
+	var name string
+	args, err := Duration("--timeout", &timeout).
+		StringSlice("--file", &files).
+		String("--name", &name).
+		ResponseFiles().
+		Parse([]string{"@" + file, "more", "--", "@literal"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if timeout != 10*time.Second || !reflect.DeepEqual(files, []string{"a b.go"}) || name != "  spaced  " {
+		t.Errorf("Unexpected values: timeout=%v files=%v name=%q", timeout, files, name)
+	}
+	if !reflect.DeepEqual(args, []string{"pkg", "more", "@literal"}) {
+		t.Errorf("Unexpected args: %v", args)
+	}
+}
+
+func TestResponseFilesDisabled(t *testing.T) {
+	args, err := New().Parse([]string{"@args.txt"})
+	if err != nil || !reflect.DeepEqual(args, []string{"@args.txt"}) {
+		t.Errorf("Expected @args.txt kept, got: %v, %v", args, err)
+	}
+	_, err = New().ResponseFiles().Parse([]string{"@" + filepath.Join(t.TempDir(), "missing.txt")})
+	if err == nil || !strings.Contains(err.Error(), "response file") {
+		t.Errorf("Expected response file error, got: %v", err)
+	}
+}
+
+func TestConfigFileLayers(t *testing.T) {
+	file := writeTestFile(t, "app.json", `{
+		"host": "config-host",
+		"port": 7070,
+		"verbose": true,
+		"file": ["a.go", "b.go"],
+		"label": {"env": "prod", "team": "infra"}
+	}`)
+	t.Setenv("TEST_CONFIG_PORT", "9090")
+
+	var host, name string
+	var port int
+	var verbose bool
+	var files []string
+	var labels map[string]string
+	res, err := ConfigFile("--config").
+		String("--host", &host).Default("localhost").
+		Int("--port", &port).Env("TEST_CONFIG_PORT").Default("8080").
+		Bool("-v,--verbose", &verbose).
+		StringSlice("--file", &files).
+		StringMap("--label", &labels).
+		String("--name", &name).Default("app").
+		ParseResult([]string{"--config", file, "--host", "cli-host"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if host != "cli-host" || port != 9090 || !verbose || name != "app" {
+		t.Errorf("Unexpected values: host=%s port=%d verbose=%v name=%s", host, port, verbose, name)
+	}
+	if !reflect.DeepEqual(files, []string{"a.go", "b.go"}) || !reflect.DeepEqual(labels, map[string]string{"env": "prod", "team": "infra"}) {
+		t.Errorf("Unexpected values: files=%v labels=%v", files, labels)
+	}
+	for name, expected := range map[string]Source{
+		"--host": SourceCLI, "--port": SourceEnv, "--verbose": SourceConfig, "--name": SourceDefault,
+	} {
+		if got := res.Source(name); got != expected {
+			t.Errorf("Source(%s) = %v, expected %v", name, got, expected)
+		}
+	}
+}
+
+func TestConfigFileDefault(t *testing.T) {
+	var host string
+	b := ConfigFile("--config").Default(filepath.Join(t.TempDir(), "missing.json")).
+		String("--host", &host).Required()
+
+	_, err := b.Parse([]string{"--host", "x"})
+	if err != nil {
+		t.Errorf("Expected missing default config to be ignored, got: %v", err)
+	}
+	_, err = b.Parse([]string{"--config", filepath.Join(t.TempDir(), "missing.json"), "--host", "x"})
+	if err == nil || !strings.Contains(err.Error(), "config file") {
+		t.Errorf("Expected missing config error, got: %v", err)
+	}
+
+	file := writeTestFile(t, "app.json", `{"host": "config-host"}`)
+	t.Setenv("TEST_CONFIG_FILE", file)
+	_, err = ConfigFile("--config").Env("TEST_CONFIG_FILE").
+		String("--host", &host).Required().
+		Parse(nil)
+	if err != nil || host != "config-host" {
+		t.Errorf("Expected config file from env to satisfy required, got: %s, %v", host, err)
+	}
+}
+
+func TestConfigFileErrors(t *testing.T) {
+	var port int
+	b := ConfigFile("--config").Int("--port", &port).
+		Command("serve", func(c *Builder) {
+			var addr string
+			c.String("--addr", &addr).Handle(func(args []string) error { return nil })
+		})
+
+	_, err := b.Parse([]string{"--config", writeTestFile(t, "a.json", `{"prot": 1}`)})
+	if err == nil || !strings.Contains(err.Error(), "unknown flag prot") {
+		t.Errorf("Expected unknown flag error, got: %v", err)
+	}
+
+	// flags of other commands are accepted
+	_, err = b.Parse([]string{"--config", writeTestFile(t, "b.json", `{"addr": ":80", "port": 1}`), "serve"})
+	if err != nil {
+		t.Errorf("Expected keys of subcommands to be accepted, got: %v", err)
+	}
+
+	file := writeTestFile(t, "c.json", `{"port": "x"}`)
+	_, err = b.Parse([]string{"--config", file})
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.File != file || !strings.Contains(err.Error(), "--port from "+file) {
+		t.Errorf("Expected value error from config file, got: %v", err)
+	}
+
+	_, err = b.Parse([]string{"--config", writeTestFile(t, "d.json", `{"port": [[1]]}`)})
+	if err == nil || !strings.Contains(err.Error(), "expect string, number or bool") {
+		t.Errorf("Expected nested value error, got: %v", err)
+	}
+}
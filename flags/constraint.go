@@ -0,0 +1,190 @@
+package flags
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Validator checks a raw flag value. Validators run after parsing
+// over every value recorded for the flag, so the target already
+// holds the value when validation fails.
+type Validator func(value string) error
+
+// ConstraintError is a single violated flag constraint
+type ConstraintError struct {
+	Flags []string // the flags involved
+	Err   error
+}
+
+func (e *ConstraintError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// ConstraintErrors collects all constraints violated in one Parse,
+// so the user can fix them at once
+type ConstraintErrors []*ConstraintError
+
+func (e ConstraintErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// flagGroup is a constraint over several flags
+type flagGroup struct {
+	names     []string
+	exclusive bool // at most one can be set, otherwise all or none
+}
+
+// Required marks the last added flag as required, it must be given
+// on the command line, through its environment variables or in the
+// config file, a default value does not count
+func (b *Builder) Required() *Builder {
+	b.lastSpec("Required").Required = true
+	return b
+}
+
+// Validate adds validators to the last added flag, they are run
+// after parsing against every raw value including environment,
+// config file and default values
+//
+//	flags.Int("--port", &port).Validate(flags.Range(1, 65535))
+func (b *Builder) Validate(validators ...Validator) *Builder {
+	spec := b.lastSpec("Validate")
+	spec.Validators = append(spec.Validators, validators...)
+	return b
+}
+
+// MutuallyExclusive declares that at most one of the flags can be set
+//
+//	flags.String("--dir", &dir).String("--pkg", &pkg).MutuallyExclusive("--dir", "--pkg")
+func (b *Builder) MutuallyExclusive(names ...string) *Builder {
+	b.groups = append(b.groups, flagGroup{names: names, exclusive: true})
+	return b
+}
+
+// RequiresAll declares that the flags must be used together:
+// if any of them is set, all of them must be set
+func (b *Builder) RequiresAll(names ...string) *Builder {
+	b.groups = append(b.groups, flagGroup{names: names})
+	return b
+}
+
+// Range returns a validator accepting numbers within [min, max]
+func Range(min float64, max float64) Validator {
+	return func(value string) error {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		if n < min || n > max {
+			return fmt.Errorf("%s is not in range [%v, %v]", value, min, max)
+		}
+		return nil
+	}
+}
+
+// Match returns a validator accepting values matching the regular expression
+func Match(pattern string) Validator {
+	re := regexp.MustCompile(pattern)
+	return func(value string) error {
+		if !re.MatchString(value) {
+			return fmt.Errorf("%q does not match %s", value, pattern)
+		}
+		return nil
+	}
+}
+
+// ExistingFile returns a validator accepting paths of existing regular files
+func ExistingFile() Validator {
+	return func(value string) error {
+		stat, err := os.Stat(value)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("file not found: %s", value)
+			}
+			return err
+		}
+		if stat.IsDir() {
+			return fmt.Errorf("%s is a directory", value)
+		}
+		return nil
+	}
+}
+
+// checkConstraints validates the flags of b and its ancestors
+// against their validators, Required and the declared groups
+func (b *Builder) checkConstraints(state *parseState) error {
+	var errs ConstraintErrors
+	for c := b; c != nil; c = c.parent {
+		for i := range c.flagSpecs {
+			spec := &c.flagSpecs[i]
+			name := displayName(spec)
+			if spec.Required && !state.set[spec] {
+				errs = append(errs, &ConstraintError{
+					Flags: []string{name},
+					Err:   fmt.Errorf("%s is required", name),
+				})
+			}
+			for _, value := range state.values[spec] {
+				for _, validate := range spec.Validators {
+					err := validate(value)
+					if err != nil {
+						errs = append(errs, &ConstraintError{
+							Flags: []string{name},
+							Err:   fmt.Errorf("invalid value for %s: %v", name, err),
+						})
+					}
+				}
+			}
+		}
+		for _, group := range c.groups {
+			var set, missing []string
+			for _, name := range group.names {
+				spec := b.findFlagSpec(name)
+				if spec == nil {
+					return fmt.Errorf("unknown flag in constraint: %s", name)
+				}
+				if state.set[spec] {
+					set = append(set, name)
+				} else {
+					missing = append(missing, name)
+				}
+			}
+			if group.exclusive && len(set) > 1 {
+				errs = append(errs, &ConstraintError{
+					Flags: set,
+					Err:   fmt.Errorf("%s are mutually exclusive", strings.Join(set, ", ")),
+				})
+			} else if !group.exclusive && len(set) > 0 && len(missing) > 0 {
+				errs = append(errs, &ConstraintError{
+					Flags: group.names,
+					Err:   fmt.Errorf("%s must be used together, missing: %s", strings.Join(group.names, ", "), strings.Join(missing, ", ")),
+				})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// displayName returns the long name of the flag if any
+func displayName(spec *FlagSpec) string {
+	for _, name := range spec.Names {
+		if strings.HasPrefix(name, "--") {
+			return name
+		}
+	}
+	return spec.Names[0]
+}
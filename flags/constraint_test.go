@@ -0,0 +1,129 @@
+package flags
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRequired(t *testing.T) {
+	var pkg string
+	_, err := String("--pkg", &pkg).Required().Parse(nil)
+	if err == nil || err.Error() != "--pkg is required" {
+		t.Errorf("Expected required error, got: %v", err)
+	}
+
+	t.Setenv("TEST_PKG", "x")
+	_, err = String("--pkg", &pkg).Required().Env("TEST_PKG").Parse(nil)
+	if err != nil {
+		t.Errorf("Expected env to satisfy required, got: %v", err)
+	}
+}
+
+func TestRequired_StructTag(t *testing.T) {
+	var opts struct {
+		Level string `flag:"--level" required:"true"`
+	}
+	_, err := BindStruct(&opts).Parse(nil)
+	if err == nil || err.Error() != "--level is required" {
+		t.Errorf("Expected required error, got: %v", err)
+	}
+	_, err = BindStruct(&opts).Parse([]string{"--level", "debug"})
+	if err != nil || opts.Level != "debug" {
+		t.Errorf("Expected level=debug, got %q, err: %v", opts.Level, err)
+	}
+}
+
+func TestMutuallyExclusive(t *testing.T) {
+	var dir, pkg string
+	b := String("--dir", &dir).String("--pkg", &pkg).MutuallyExclusive("--dir", "--pkg")
+
+	_, err := b.Parse([]string{"--dir", "a"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	_, err = b.Parse([]string{"--dir", "a", "--pkg", "b"})
+	if err == nil || err.Error() != "--dir, --pkg are mutually exclusive" {
+		t.Errorf("Expected mutually exclusive error, got: %v", err)
+	}
+}
+
+func TestRequiresAll(t *testing.T) {
+	var cert, key string
+	b := String("--cert", &cert).String("--key", &key).RequiresAll("--cert", "--key")
+
+	_, err := b.Parse(nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	_, err = b.Parse([]string{"--cert", "a.pem"})
+	if err == nil || err.Error() != "--cert, --key must be used together, missing: --key" {
+		t.Errorf("Expected requires all error, got: %v", err)
+	}
+}
+
+func TestValidators(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(file, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var port int
+	var name, config string
+	b := Int("-p,--port", &port).Validate(Range(1, 65535)).
+		String("--name", &name).Validate(Match(`^[a-z]+$`)).
+		String("--config", &config).Validate(ExistingFile())
+
+	_, err := b.Parse([]string{"-p", "8080", "--name", "web", "--config", file})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	_, err = b.Parse([]string{"-p", "0", "--name", "Web", "--config", dir})
+	var errs ConstraintErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ConstraintErrors, got: %T %v", err, err)
+	}
+	expect := []string{
+		"invalid value for --port: 0 is not in range [1, 65535]",
+		`invalid value for --name: "Web" does not match ^[a-z]+$`,
+		"invalid value for --config: " + dir + " is a directory",
+	}
+	if len(errs) != len(expect) {
+		t.Fatalf("Expected %d errors, got: %v", len(expect), errs)
+	}
+	for i, e := range errs {
+		if e.Error() != expect[i] {
+			t.Errorf("errs[%d]: expect %q, got %q", i, expect[i], e.Error())
+		}
+	}
+}
+
+func TestConstraints_ReportedTogether(t *testing.T) {
+	var dir, pkg, out string
+	_, err := String("--dir", &dir).String("--pkg", &pkg).
+		String("--out", &out).Required().
+		MutuallyExclusive("--dir", "--pkg").
+		Parse([]string{"--dir", "a", "--pkg", "b"})
+	var errs ConstraintErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("Expected 2 constraint errors, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "--out is required\n--dir, --pkg are mutually exclusive") {
+		t.Errorf("Unexpected message: %v", err)
+	}
+	if strings.Join(errs[1].Flags, " ") != "--dir --pkg" {
+		t.Errorf("Expected flags of the group, got %v", errs[1].Flags)
+	}
+}
+
+func TestConstraints_UnknownFlag(t *testing.T) {
+	var dir string
+	_, err := String("--dir", &dir).MutuallyExclusive("--dir", "--pkg").Parse(nil)
+	if err == nil || !strings.Contains(err.Error(), "unknown flag in constraint: --pkg") {
+		t.Errorf("Expected unknown flag error, got: %v", err)
+	}
+}
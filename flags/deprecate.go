@@ -0,0 +1,75 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Deprecated marks the last added flag as deprecated, using it still
+// works but prints a warning with message to stderr, see Stderr
+//
+//	flags.Bool("--legacy", &legacy).Deprecated("it has no effect since v1.2")
+func (b *Builder) Deprecated(message string) *Builder {
+	spec := b.lastSpec("Deprecated")
+	spec.Deprecated = message
+	if spec.Deprecated == "" {
+		spec.Deprecated = "it will be removed in a future release"
+	}
+	return b
+}
+
+// DeprecatedAlias adds old names to the last added flag, they keep
+// working after a rename but print a warning to stderr
+//
+//	flags.String("--out-dir", &outDir).DeprecatedAlias("--output")
+func (b *Builder) DeprecatedAlias(names ...string) *Builder {
+	spec := b.lastSpec("DeprecatedAlias")
+	spec.DeprecatedNames = append(spec.DeprecatedNames, names...)
+	return b
+}
+
+// Hidden excludes the last added flag from help, docs and completion
+func (b *Builder) Hidden() *Builder {
+	b.lastSpec("Hidden").Hidden = true
+	return b
+}
+
+// Removed adds a flag which has been removed, using it fails with
+// message telling how to migrate. It is hidden from help.
+//
+//	flags.New().Removed("--pkg", "use --dir instead")
+func (b *Builder) Removed(names string, message string) *Builder {
+	b.flagSpecs = append(b.flagSpecs, FlagSpec{
+		Names:   parseNames(names),
+		Type:    FlagTypeValue,
+		Hidden:  true,
+		Removed: message,
+	})
+	return b
+}
+
+// hidden reports whether the flag is left out of help, docs and completion
+func (spec *FlagSpec) hidden() bool {
+	return spec.Hidden || spec.Removed != ""
+}
+
+// checkUsable fails if the flag has been removed and warns if flag,
+// the name used on the command line, is deprecated. Each name is
+// warned about once per Parse.
+func (b *Builder) checkUsable(spec *FlagSpec, flag string, index int, state *parseState) error {
+	if spec.Removed != "" {
+		return &ParseError{Kind: KindRemovedFlag, Name: flag, Index: index, Err: errors.New(spec.Removed)}
+	}
+	var warning string
+	if spec.Deprecated != "" {
+		warning = fmt.Sprintf("flag %s is deprecated: %s", flag, spec.Deprecated)
+	} else if containsString(spec.DeprecatedNames, flag) {
+		warning = fmt.Sprintf("flag %s is deprecated, use %s instead", flag, displayName(spec))
+	}
+	if warning == "" || state.warned[flag] {
+		return nil
+	}
+	state.warned[flag] = true
+	fmt.Fprintf(b.errWriter(), "warning: %s\n", warning)
+	return nil
+}
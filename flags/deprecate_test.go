@@ -0,0 +1,91 @@
+package flags
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDeprecatedAlias(t *testing.T) {
+	var stderr strings.Builder
+	var outDir string
+	b := String("--out-dir", &outDir).DeprecatedAlias("--output", "-o").Stderr(&stderr)
+
+	_, err := b.Parse([]string{"--output", "a", "--output=b"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if outDir != "b" {
+		t.Errorf("Expected outDir=b, got %q", outDir)
+	}
+	if stderr.String() != "warning: flag --output is deprecated, use --out-dir instead\n" {
+		t.Errorf("Unexpected warnings: %q", stderr.String())
+	}
+
+	stderr.Reset()
+	_, err = b.Parse([]string{"--out-dir", "c"})
+	if err != nil || stderr.Len() > 0 {
+		t.Errorf("Expected no warning for the new name, got: %q, %v", stderr.String(), err)
+	}
+	if strings.Contains(b.Usage(), "--output") {
+		t.Errorf("Expected deprecated alias not shown in usage:\n%s", b.Usage())
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	var stderr strings.Builder
+	var legacy, verbose bool
+	_, err := Bool("-l,--legacy", &legacy).Deprecated("it has no effect since v1.2").
+		Bool("-v", &verbose).
+		POSIX().
+		Stderr(&stderr).
+		Parse([]string{"-vl"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !legacy || !verbose {
+		t.Errorf("Expected flags to be set")
+	}
+	if stderr.String() != "warning: flag -l is deprecated: it has no effect since v1.2\n" {
+		t.Errorf("Unexpected warnings: %q", stderr.String())
+	}
+}
+
+func TestHidden(t *testing.T) {
+	var debug, verbose bool
+	b := Bool("--debug", &debug).Hidden().
+		Bool("--verbose", &verbose).
+		Name("app")
+
+	_, err := b.Parse([]string{"--debug"})
+	if err != nil || !debug {
+		t.Errorf("Expected hidden flag to parse, got: %v", err)
+	}
+	if strings.Contains(b.Usage(), "--debug") || strings.Contains(b.Markdown(), "--debug") {
+		t.Errorf("Expected hidden flag not shown")
+	}
+	if got := formatCompletions(b.complete([]string{"--"})); strings.Contains(got, "--debug") {
+		t.Errorf("Expected hidden flag not completed, got: %s", got)
+	}
+	_, err = b.Parse([]string{"--debu"})
+	if err == nil || err.Error() != "unrecognized flag: --debu" {
+		t.Errorf("Expected no suggestion of hidden flag, got: %v", err)
+	}
+}
+
+func TestRemoved(t *testing.T) {
+	var dir string
+	b := String("--dir", &dir).Removed("--pkg,-p", "use --dir instead")
+
+	_, err := b.Parse([]string{"--pkg", "x"})
+	if err == nil || err.Error() != "flag --pkg has been removed: use --dir instead" {
+		t.Errorf("Expected removed flag error, got: %v", err)
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Kind != KindRemovedFlag || perr.Index != 0 {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if strings.Contains(b.Usage(), "--pkg") {
+		t.Errorf("Expected removed flag not shown in usage")
+	}
+}
@@ -0,0 +1,271 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DocsCommand adds a subcommand printing the reference of the
+// program in the format given as its argument: man or markdown
+//
+//	myapp docs man > myapp.1
+func (b *Builder) DocsCommand(name string) *Builder {
+	return b.Command(name, func(c *Builder) {
+		c.Description("print the reference as a man page or Markdown").
+			Handle(func(args []string) error {
+				format, err := OnlyArg(args)
+				if err != nil {
+					return err
+				}
+				var doc string
+				switch format {
+				case "man":
+					doc = b.ManPage(1)
+				case "markdown", "md":
+					doc = b.Markdown()
+				default:
+					return fmt.Errorf("unsupported format: %s, expect man or markdown", format)
+				}
+				fmt.Fprint(b.outWriter(), doc)
+				return nil
+			})
+	})
+}
+
+// ManPage renders the command, its subcommands, arguments, flags
+// and environment variables as a roff man page of section
+func (b *Builder) ManPage(section int) string {
+	var sb strings.Builder
+	prog := b.programPath()
+	fmt.Fprintf(&sb, ".TH %q %q\n", strings.ToUpper(strings.ReplaceAll(prog, " ", "-")), fmt.Sprint(section))
+	sb.WriteString(".SH NAME\n")
+	sb.WriteString(roffEscape(prog))
+	if desc := firstLine(b.description); desc != "" {
+		sb.WriteString(" \\- " + roffEscape(desc))
+	}
+	sb.WriteString("\n.SH SYNOPSIS\n")
+	writeManSynopsis(&sb, b)
+	if b.description != "" {
+		sb.WriteString(".SH DESCRIPTION\n")
+		writeManText(&sb, b.description)
+	}
+	writeManArgs(&sb, ".SH ARGUMENTS\n", b)
+	writeManFlags(&sb, ".SH OPTIONS\n", b)
+
+	var cmds []*Builder
+	b.walkCommands(func(cmd *Builder) {
+		cmds = append(cmds, cmd)
+	})
+	if len(cmds) > 0 {
+		sb.WriteString(".SH COMMANDS\n")
+		for _, cmd := range cmds {
+			fmt.Fprintf(&sb, ".SS %q\n", roffEscape(cmd.programPath()))
+			if cmd.description != "" {
+				writeManText(&sb, cmd.description)
+				sb.WriteString(".PP\n")
+			}
+			writeManSynopsis(&sb, cmd)
+			writeManArgs(&sb, ".PP\nArguments:\n", cmd)
+			writeManFlags(&sb, ".PP\nOptions:\n", cmd)
+		}
+	}
+
+	var envRows [][2]string
+	b.walkFlags(func(spec *FlagSpec) {
+		for _, env := range spec.EnvVars {
+			envRows = append(envRows, [2]string{env, "value of " + displayName(spec)})
+		}
+	})
+	if len(envRows) > 0 {
+		sb.WriteString(".SH ENVIRONMENT\n")
+		for _, row := range envRows {
+			fmt.Fprintf(&sb, ".TP\n.B %s\n%s\n", roffEscape(row[0]), roffEscape(row[1]))
+		}
+	}
+	return sb.String()
+}
+
+func writeManSynopsis(sb *strings.Builder, cmd *Builder) {
+	path := cmd.programPath()
+	fmt.Fprintf(sb, ".B %s\n%s\n", roffEscape(path), roffEscape(strings.TrimSpace(strings.TrimPrefix(cmd.usageLine(), path))))
+}
+
+func writeManText(sb *strings.Builder, text string) {
+	for i, para := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if i > 0 {
+			sb.WriteString(".PP\n")
+		}
+		for _, line := range strings.Split(para, "\n") {
+			sb.WriteString(roffLine(line))
+			sb.WriteString("\n")
+		}
+	}
+}
+
+func writeManArgs(sb *strings.Builder, title string, cmd *Builder) {
+	if len(cmd.argSpecs) == 0 {
+		return
+	}
+	sb.WriteString(title)
+	for _, spec := range cmd.argSpecs {
+		fmt.Fprintf(sb, ".TP\n\\fI%s\\fR\n%s\n", roffEscape(spec.Name), roffEscape(argDesc(spec)))
+	}
+}
+
+func writeManFlags(sb *strings.Builder, title string, cmd *Builder) {
+	specs := cmd.visibleFlags()
+	if len(specs) == 0 {
+		return
+	}
+	sb.WriteString(title)
+	for _, spec := range specs {
+		names := make([]string, 0, len(spec.Names))
+		for _, name := range spec.Names {
+			names = append(names, "\\fB"+roffEscape(name)+"\\fR")
+		}
+		head := strings.Join(names, ", ")
+		if !spec.isBool() {
+			head += " \\fI" + roffEscape(flagPlaceholder(spec)) + "\\fR"
+		}
+		fmt.Fprintf(sb, ".TP\n%s\n", head)
+		if desc := flagUsageDesc(spec); desc != "" {
+			sb.WriteString(roffLine(desc))
+			sb.WriteString("\n")
+		}
+	}
+}
+
+// roffEscape escapes backslashes and dashes for roff
+func roffEscape(s string) string {
+	return strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+}
+
+// roffLine escapes a text line, protecting a leading control character
+func roffLine(s string) string {
+	s = roffEscape(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// Markdown renders the command, its subcommands, arguments, flags
+// and environment variables as a Markdown reference page
+func (b *Builder) Markdown() string {
+	var sb strings.Builder
+	writeMarkdown(&sb, b, "#")
+	b.walkCommands(func(cmd *Builder) {
+		sb.WriteString("\n")
+		writeMarkdown(&sb, cmd, "##")
+	})
+	return sb.String()
+}
+
+func writeMarkdown(sb *strings.Builder, cmd *Builder, heading string) {
+	fmt.Fprintf(sb, "%s %s\n\n", heading, cmd.programPath())
+	if cmd.description != "" {
+		sb.WriteString(strings.TrimSpace(cmd.description))
+		sb.WriteString("\n\n")
+	}
+	fmt.Fprintf(sb, "```\n%s\n```\n", cmd.usageLine())
+
+	sub := heading + "#"
+	if len(cmd.commands) > 0 {
+		fmt.Fprintf(sb, "\n%s Commands\n\n| Command | Description |\n| --- | --- |\n", sub)
+		for _, c := range cmd.commands {
+			fmt.Fprintf(sb, "| [`%s`](#%s) | %s |\n", c.name, markdownAnchor(c.programPath()), markdownCell(firstLine(c.description)))
+		}
+	}
+	if len(cmd.argSpecs) > 0 {
+		fmt.Fprintf(sb, "\n%s Arguments\n\n| Argument | Description |\n| --- | --- |\n", sub)
+		for _, spec := range cmd.argSpecs {
+			fmt.Fprintf(sb, "| `%s` | %s |\n", spec.Name, markdownCell(argDesc(spec)))
+		}
+	}
+	if specs := cmd.visibleFlags(); len(specs) > 0 {
+		fmt.Fprintf(sb, "\n%s Options\n\n| Option | Description | Default | Environment |\n| --- | --- | --- | --- |\n", sub)
+		for _, spec := range specs {
+			desc := spec.Description
+			if cv, ok := spec.Value.(choicesValue); ok {
+				desc = strings.TrimSpace(fmt.Sprintf("%s (one of: %s)", desc, strings.Join(cv.Choices(), ", ")))
+			}
+			if spec.Required {
+				desc = strings.TrimSpace(desc + " (required)")
+			}
+			var def string
+			if spec.Default != "" {
+				def = "`" + spec.Default + "`"
+			}
+			envs := make([]string, 0, len(spec.EnvVars))
+			for _, env := range spec.EnvVars {
+				envs = append(envs, "`"+env+"`")
+			}
+			fmt.Fprintf(sb, "| `%s` | %s | %s | %s |\n", flagUsageName(spec), markdownCell(desc), def, strings.Join(envs, ", "))
+		}
+	}
+}
+
+// markdownAnchor returns the anchor GitHub generates for a heading
+func markdownAnchor(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ':
+			sb.WriteRune('-')
+		case r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+// argDesc describes a positional argument like: int, optional
+func argDesc(spec ArgSpec) string {
+	typ := "string"
+	if spec.Value != nil {
+		typ = spec.Value.Type()
+	}
+	switch {
+	case spec.Variadic:
+		return typ + ", optional, repeated"
+	case spec.Optional:
+		return typ + ", optional"
+	default:
+		return typ + ", required"
+	}
+}
+
+// walkCommands calls fn for the subcommands of b, depth first
+func (b *Builder) walkCommands(fn func(cmd *Builder)) {
+	for _, cmd := range b.commands {
+		fn(cmd)
+		cmd.walkCommands(fn)
+	}
+}
+
+// walkFlags calls fn for the visible flags of b and its subcommands
+func (b *Builder) walkFlags(fn func(spec *FlagSpec)) {
+	for _, spec := range b.visibleFlags() {
+		fn(spec)
+	}
+	b.walkCommands(func(cmd *Builder) {
+		for _, spec := range cmd.visibleFlags() {
+			fn(spec)
+		}
+	})
+}
+
+// visibleFlags returns the flags of b which are not hidden
+func (b *Builder) visibleFlags() []*FlagSpec {
+	specs := make([]*FlagSpec, 0, len(b.flagSpecs))
+	for i := range b.flagSpecs {
+		if !b.flagSpecs[i].hidden() {
+			specs = append(specs, &b.flagSpecs[i])
+		}
+	}
+	return specs
+}
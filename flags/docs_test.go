@@ -0,0 +1,107 @@
+package flags
+
+import (
+	"strings"
+	"testing"
+)
+
+func newDocsBuilder() *Builder {
+	var verbose bool
+	var port int
+	var pkg string
+	var files []string
+	return New().Name("myapp").Description("myapp serves code.\n\n.dot files are skipped.").
+		Bool("-v,--verbose", &verbose).Desc("enable verbose output").
+		Int("--port", &port).Env("APP_PORT").Default("8080").Desc("listen port").
+		Command("gen", func(c *Builder) {
+			c.Description("generate code").
+				String("--pkg", &pkg).Required().
+				Args("FILES", &files).
+				Handle(func(args []string) error { return nil })
+		})
+}
+
+func TestManPage(t *testing.T) {
+	expected := `.TH "MYAPP" "1"
+.SH NAME
+myapp \- myapp serves code.
+.SH SYNOPSIS
+.B myapp
+<command> [OPTIONS]
+.SH DESCRIPTION
+myapp serves code.
+.PP
+\&.dot files are skipped.
+.SH OPTIONS
+.TP
+\fB\-v\fR, \fB\-\-verbose\fR
+enable verbose output
+.TP
+\fB\-\-port\fR \fIINT\fR
+listen port (env: $APP_PORT, default: 8080)
+.SH COMMANDS
+.SS "myapp gen"
+generate code
+.PP
+.B myapp gen
+[OPTIONS] [FILES...]
+.PP
+Arguments:
+.TP
+\fIFILES\fR
+strings, optional, repeated
+.PP
+Options:
+.TP
+\fB\-\-pkg\fR \fISTRING\fR
+(required)
+.SH ENVIRONMENT
+.TP
+.B APP_PORT
+value of \-\-port
+`
+	if got := newDocsBuilder().ManPage(1); got != expected {
+		t.Errorf("Unexpected man page:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	expected := "# myapp\n\nmyapp serves code.\n\n.dot files are skipped.\n\n" +
+		"```\nmyapp <command> [OPTIONS]\n```\n\n" +
+		"## Commands\n\n| Command | Description |\n| --- | --- |\n| [`gen`](#myapp-gen) | generate code |\n\n" +
+		"## Options\n\n| Option | Description | Default | Environment |\n| --- | --- | --- | --- |\n" +
+		"| `-v, --verbose` | enable verbose output |  |  |\n" +
+		"| `--port INT` | listen port | `8080` | `APP_PORT` |\n\n" +
+		"## myapp gen\n\ngenerate code\n\n" +
+		"```\nmyapp gen [OPTIONS] [FILES...]\n```\n\n" +
+		"### Arguments\n\n| Argument | Description |\n| --- | --- |\n| `FILES` | strings, optional, repeated |\n\n" +
+		"### Options\n\n| Option | Description | Default | Environment |\n| --- | --- | --- | --- |\n" +
+		"| `--pkg STRING` | (required) |  |  |\n"
+	if got := newDocsBuilder().Markdown(); got != expected {
+		t.Errorf("Unexpected markdown:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestDocsCommand(t *testing.T) {
+	var out strings.Builder
+	b := newDocsBuilder().DocsCommand("docs").Stdout(&out)
+
+	_, err := b.Parse([]string{"docs", "markdown"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "# myapp\n") || !strings.Contains(out.String(), "## myapp docs") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+
+	_, err = b.Parse([]string{"docs", "html"})
+	if err == nil || err.Error() != "unsupported format: html, expect man or markdown" {
+		t.Errorf("Expected unsupported format error, got: %v", err)
+	}
+}
+
+func TestRoffEscape(t *testing.T) {
+	if got := roffLine(`'quoted' --flag C:\dir`); got != `\&'quoted' \-\-flag C:\edir` {
+		t.Errorf("Unexpected escape: %s", got)
+	}
+}
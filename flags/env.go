@@ -0,0 +1,118 @@
+package flags
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Env sets the environment variables of the last added flag.
+// When the flag is absent from the command line, the first
+// non-empty variable is parsed as its value, taking precedence
+// over the default value.
+//
+//	flags.Int("--port", &port).Env("APP_PORT").Default("8080")
+func (b *Builder) Env(names ...string) *Builder {
+	spec := b.lastSpec("Env")
+	spec.EnvVars = append(spec.EnvVars, names...)
+	return b
+}
+
+// applyFallbacks fills flags of b and its ancestors that were not
+// set on the command line, first from the environment, then from
+// the config file and finally from their default values
+func (b *Builder) applyFallbacks(state *parseState) error {
+	// config file flags go first as they provide values to the others
+	var file string
+	var config map[string][]string
+	for c := b; c != nil; c = c.parent {
+		for i := range c.flagSpecs {
+			spec := &c.flagSpecs[i]
+			if !spec.config {
+				continue
+			}
+			err := state.applyFallback(spec, "", nil)
+			if err != nil {
+				return err
+			}
+			if config != nil {
+				continue
+			}
+			file, config, err = state.loadConfig(spec)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !b.root().hasConfigKey(key) {
+			return fmt.Errorf("config file %s: unknown flag %s", file, key)
+		}
+	}
+
+	for c := b; c != nil; c = c.parent {
+		for i := range c.flagSpecs {
+			spec := &c.flagSpecs[i]
+			if spec.config {
+				continue
+			}
+			err := state.applyFallback(spec, file, config)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyFallback fills the flag if it was not set on the command line
+func (s *parseState) applyFallback(spec *FlagSpec, file string, config map[string][]string) error {
+	if s.set[spec] {
+		return nil
+	}
+	env, value, ok := lookupEnv(spec.EnvVars)
+	if ok {
+		err := s.setFlag(spec, value, SourceEnv)
+		if err != nil {
+			return &ParseError{Kind: KindInvalidValue, Name: spec.Names[0], Index: -1, Env: env, Err: err}
+		}
+		return nil
+	}
+	for _, key := range configKeys(spec) {
+		values, ok := config[key]
+		if !ok || len(values) == 0 {
+			continue
+		}
+		for _, value := range values {
+			err := s.setFlag(spec, value, SourceConfig)
+			if err != nil {
+				return &ParseError{Kind: KindInvalidValue, Name: spec.Names[0], Index: -1, File: file, Err: err}
+			}
+		}
+		return nil
+	}
+	if spec.Default == "" {
+		return nil
+	}
+	err := s.setFlag(spec, spec.Default, SourceDefault)
+	if err != nil {
+		return fmt.Errorf("error setting default value for %s: %v", spec.Names[0], err)
+	}
+	return nil
+}
+
+// lookupEnv returns the first non-empty environment variable
+func lookupEnv(names []string) (string, string, bool) {
+	for _, name := range names {
+		value := os.Getenv(name)
+		if value != "" {
+			return name, value, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,71 @@
+package flags
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnv(t *testing.T) {
+	t.Setenv("APP_PORT", "9090")
+	t.Setenv("APP_NAME", "")
+	t.Setenv("NAME", "env-name")
+
+	var port int
+	var name string
+	_, err := Int("--port", &port).Env("APP_PORT").Default("8080").
+		String("--name", &name).Env("APP_NAME", "NAME").
+		Parse(nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if port != 9090 {
+		t.Errorf("Expected port=9090 from env, got %d", port)
+	}
+	if name != "env-name" {
+		t.Errorf("Expected name='env-name' from second env, got '%s'", name)
+	}
+}
+
+func TestEnv_CommandLineWins(t *testing.T) {
+	t.Setenv("APP_PORT", "9090")
+
+	var port int
+	_, err := Int("--port", &port).Env("APP_PORT").Parse([]string{"--port", "7070"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if port != 7070 {
+		t.Errorf("Expected port=7070 from command line, got %d", port)
+	}
+}
+
+func TestEnv_DefaultWhenUnset(t *testing.T) {
+	t.Setenv("APP_PORT", "")
+
+	var port int
+	_, err := Int("--port", &port).Env("APP_PORT").Default("8080").Parse(nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if port != 8080 {
+		t.Errorf("Expected port=8080 from default, got %d", port)
+	}
+}
+
+func TestEnv_InvalidValue(t *testing.T) {
+	t.Setenv("APP_PORT", "abc")
+
+	var port int
+	_, err := Int("--port", &port).Env("APP_PORT").Parse(nil)
+	if err == nil || !strings.Contains(err.Error(), "--port from $APP_PORT") {
+		t.Errorf("Expected error naming $APP_PORT, got: %v", err)
+	}
+}
+
+func TestEnv_Usage(t *testing.T) {
+	var port int
+	usage := Int("--port", &port).Desc("listen port").Env("APP_PORT", "PORT").Default("8080").Usage()
+	if !strings.Contains(usage, "--port INT  listen port (env: $APP_PORT, $PORT, default: 8080)") {
+		t.Errorf("Expected env and default in usage, got:\n%s", usage)
+	}
+}
@@ -0,0 +1,200 @@
+package flags
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrorKind classifies a ParseError
+type ErrorKind int
+
+const (
+	KindUnknownFlag    ErrorKind = iota + 1 // flag not registered
+	KindUnknownCommand                      // subcommand not registered
+	KindMissingCommand                      // a subcommand is required
+	KindMissingValue                        // flag given without its value
+	KindInvalidValue                        // flag value rejected by its Value
+	KindMissingArg                          // required positional argument absent
+	KindInvalidArg                          // positional argument rejected by its Value
+	KindExtraArg                            // more positional arguments than declared
+	KindRemovedFlag                         // flag removed, Err tells how to migrate
+)
+
+var kindNames = map[ErrorKind]string{
+	KindUnknownFlag:    "unknown flag",
+	KindUnknownCommand: "unknown command",
+	KindMissingCommand: "missing command",
+	KindMissingValue:   "missing value",
+	KindInvalidValue:   "invalid value",
+	KindMissingArg:     "missing argument",
+	KindInvalidArg:     "invalid argument",
+	KindExtraArg:       "extra argument",
+	KindRemovedFlag:    "removed flag",
+}
+
+func (k ErrorKind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("ErrorKind(%d)", int(k))
+}
+
+// ParseError is returned by Parse when the command line is invalid
+type ParseError struct {
+	Kind  ErrorKind
+	Name  string // the flag, command or argument name involved
+	Index int    // index in the parsed args, -1 if not from args
+	Env   string // the environment variable providing the value, if any
+	File  string // the config file providing the value, if any
+	Err   error  // underlying error of invalid values
+
+	// Suggestions are registered names close to an unknown
+	// flag or command
+	Suggestions []string
+}
+
+func (e *ParseError) Error() string {
+	var msg string
+	switch e.Kind {
+	case KindUnknownFlag:
+		msg = "unrecognized flag: " + e.Name
+	case KindUnknownCommand:
+		msg = "unrecognized command: " + e.Name
+	case KindMissingCommand:
+		msg = strings.TrimSpace(e.Name + " requires a subcommand")
+	case KindMissingValue:
+		msg = e.Name + " requires a value"
+	case KindInvalidValue:
+		if e.Env != "" {
+			msg = fmt.Sprintf("error setting value for %s from $%s: %v", e.Name, e.Env, e.Err)
+		} else if e.File != "" {
+			msg = fmt.Sprintf("error setting value for %s from %s: %v", e.Name, e.File, e.Err)
+		} else {
+			msg = fmt.Sprintf("error setting value for %s: %v", e.Name, e.Err)
+		}
+	case KindMissingArg:
+		msg = "missing argument " + e.Name
+	case KindInvalidArg:
+		msg = fmt.Sprintf("invalid argument %s: %v", e.Name, e.Err)
+	case KindExtraArg:
+		msg = "unexpected argument: " + e.Name
+	case KindRemovedFlag:
+		msg = fmt.Sprintf("flag %s has been removed: %v", e.Name, e.Err)
+	default:
+		msg = fmt.Sprintf("%v: %s", e.Kind, e.Name)
+	}
+	if len(e.Suggestions) > 0 {
+		msg += ", did you mean " + strings.Join(e.Suggestions, " or ") + "?"
+	}
+	return msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// IsValueError reports whether the error is caused by a malformed
+// value rather than by the shape of the command line
+func (e *ParseError) IsValueError() bool {
+	return e.Kind == KindInvalidValue || e.Kind == KindInvalidArg
+}
+
+// unknownFlagError builds the error for an unknown flag with
+// suggestions from the flags visible in cmd
+func unknownFlagError(cmd *Builder, flag string, index int) *ParseError {
+	var names []string
+	for c := cmd; c != nil; c = c.parent {
+		for _, spec := range c.flagSpecs {
+			if !spec.hidden() {
+				names = append(names, spec.Names...)
+			}
+		}
+	}
+	return &ParseError{
+		Kind:        KindUnknownFlag,
+		Name:        flag,
+		Index:       index,
+		Suggestions: suggest(flag, names),
+	}
+}
+
+// unknownCommandError builds the error for an unknown subcommand
+// of cmd with suggestions from its subcommands
+func unknownCommandError(cmd *Builder, name string, index int) *ParseError {
+	names := make([]string, 0, len(cmd.commands))
+	for _, c := range cmd.commands {
+		names = append(names, c.name)
+	}
+	return &ParseError{
+		Kind:        KindUnknownCommand,
+		Name:        name,
+		Index:       index,
+		Suggestions: suggest(name, names),
+	}
+}
+
+// suggest returns the candidates closest to name by edit distance,
+// ignoring those too different to be a typo
+func suggest(name string, candidates []string) []string {
+	maxDist := len(name) / 3
+	if maxDist < 2 {
+		maxDist = 2
+	}
+	best := maxDist
+	var result []string
+	for _, candidate := range candidates {
+		d := editDistance(name, candidate)
+		if d == 0 || d > best {
+			continue
+		}
+		if d < best {
+			best = d
+			result = result[:0]
+		}
+		if !containsString(result, candidate) {
+			result = append(result, candidate)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a int, rest ...int) int {
+	for _, v := range rest {
+		if v < a {
+			a = v
+		}
+	}
+	return a
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package flags
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestParseErrorSuggestFlag(t *testing.T) {
+	var verbose, version bool
+	b := Bool("-v,--verbose", &verbose).Bool("--version", &version)
+
+	_, err := b.Parse([]string{"a", "--verbos"})
+	if err == nil || err.Error() != "unrecognized flag: --verbos, did you mean --verbose?" {
+		t.Fatalf("Expected suggestion, got: %v", err)
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Expected *ParseError, got: %T", err)
+	}
+	if perr.Kind != KindUnknownFlag || perr.Name != "--verbos" || perr.Index != 1 {
+		t.Errorf("Unexpected error fields: %+v", perr)
+	}
+	if perr.IsValueError() {
+		t.Errorf("Expected usage error")
+	}
+
+	_, err = b.Parse([]string{"--color"})
+	if err == nil || err.Error() != "unrecognized flag: --color" {
+		t.Errorf("Expected no suggestion, got: %v", err)
+	}
+}
+
+func TestParseErrorSuggestCommand(t *testing.T) {
+	b := New().
+		Command("build", func(c *Builder) { c.Handle(func(args []string) error { return nil }) }).
+		Command("bench", func(c *Builder) { c.Handle(func(args []string) error { return nil }) })
+
+	_, err := b.Parse([]string{"buidl"})
+	if err == nil || err.Error() != "unrecognized command: buidl, did you mean build?" {
+		t.Fatalf("Expected suggestion, got: %v", err)
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Kind != KindUnknownCommand || perr.Index != 0 {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+
+	_, err = b.Parse([]string{"bnch"})
+	if err == nil || err.Error() != "unrecognized command: bnch, did you mean bench?" {
+		t.Errorf("Expected suggestion, got: %v", err)
+	}
+}
+
+func TestParseErrorValue(t *testing.T) {
+	var port int
+	b := Int("--port", &port)
+
+	_, err := b.Parse([]string{"--port", "x"})
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Expected *ParseError, got: %v", err)
+	}
+	if perr.Kind != KindInvalidValue || perr.Name != "--port" || perr.Index != 0 || !perr.IsValueError() {
+		t.Errorf("Unexpected error fields: %+v", perr)
+	}
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Errorf("Expected to unwrap to *strconv.NumError, got: %v", perr.Err)
+	}
+
+	_, err = b.Parse([]string{"--port"})
+	if !errors.As(err, &perr) || perr.Kind != KindMissingValue || perr.IsValueError() {
+		t.Errorf("Expected missing value, got: %v", err)
+	}
+}
+
+func TestParseErrorArgs(t *testing.T) {
+	var pkg string
+	var count int
+	b := Arg("PKG", &pkg).Arg("COUNT", &count)
+
+	tests := []struct {
+		args  []string
+		kind  ErrorKind
+		index int
+	}{
+		{[]string{"x"}, KindMissingArg, -1},
+		{[]string{"x", "--", "y"}, KindInvalidArg, 2},
+		{[]string{"x", "1", "z"}, KindExtraArg, 2},
+	}
+	for _, tt := range tests {
+		_, err := b.Parse(tt.args)
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Kind != tt.kind || perr.Index != tt.index {
+			t.Errorf("Parse(%v): expected %v at %d, got: %v", tt.args, tt.kind, tt.index, err)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []string
+		expected   []string
+	}{
+		{"--verbos", []string{"--verbose", "--version"}, []string{"--verbose"}},
+		{"--outptu", []string{"--output", "--outdir"}, []string{"--output"}},
+		{"-x", []string{"-v", "-q"}, []string{"-q", "-v"}},
+		{"--color", []string{"--verbose"}, nil},
+	}
+	for _, tt := range tests {
+		got := suggest(tt.name, tt.candidates)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("suggest(%q) = %v, expected %v", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "abc", 3},
+		{"build", "buidl", 2},
+		{"kitten", "sitting", 3},
+		{"same", "same", 0},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
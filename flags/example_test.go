@@ -151,3 +151,29 @@ func TestExampleWithDoublePointers(t *testing.T) {
 		t.Errorf("Expected remainArgs=['remaining'], got %v", remainArgs)
 	}
 }
+
+func ExampleBuilder_Usage() {
+	var timeout time.Duration
+	var files []string
+	var verbose bool
+
+	_, err := Duration("--timeout", &timeout).Desc("set timeout duration").
+		StringSlice("--file", &files).Placeholder("FILE").Desc("add files to process, can be repeated").
+		Bool("-v,--verbose", &verbose).Desc("enable verbose output").
+		Help("-h,--help", "").
+		Name("myapp").
+		HelpNoExit().
+		Parse([]string{"--help"})
+
+	fmt.Printf("Error: %v\n", err)
+
+	// Output:
+	// Usage: myapp [OPTIONS]
+	//
+	// Options:
+	//   --timeout DURATION  set timeout duration
+	//   --file FILE         add files to process, can be repeated
+	//   -v, --verbose       enable verbose output
+	//   -h, --help          show help message
+	// Error: help
+}
@@ -0,0 +1,312 @@
+package flagsgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// Flag is a flag registered by a builder chain
+type Flag struct {
+	Names    []string
+	Method   string // builder method like Duration
+	Default  string
+	Env      []string
+	Required bool
+	Hidden   bool // hidden or removed, so not expected in help
+	Pos      token.Pos
+}
+
+// Problem is a disagreement between a help text and a builder chain
+type Problem struct {
+	Pos     token.Pos // position of the flag in the chain, or NoPos
+	Message string
+}
+
+// flagMethods take the flag names as the first argument
+var flagMethods = map[string]bool{
+	"String": true, "Bool": true, "Duration": true, "Int": true,
+	"StringSlice": true, "Float": true, "IntSlice": true, "StringMap": true,
+	"Time": true, "Enum": true, "Count": true, "Var": true,
+	"Help": true, "HelpFunc": true, "ConfigFile": true, "Removed": true,
+}
+
+// CheckFile checks the help text declared as constant helpConst
+// in the Go file against the builder chain registering it with
+// Help, or against the only builder chain of the file. Problems
+// are returned as messages prefixed by their position.
+func CheckFile(file string, helpConst string) ([]string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	help, ok := FindString(f, helpConst)
+	if !ok {
+		return nil, fmt.Errorf("%s: string constant %s not found", file, helpConst)
+	}
+	chains := FindChains(f, "flags")
+	var chain []*Flag
+	var chainPos token.Pos
+	for _, c := range chains {
+		if c.helpConst == helpConst {
+			chain, chainPos = c.flags, c.pos
+			break
+		}
+	}
+	if chain == nil {
+		if len(chains) != 1 {
+			return nil, fmt.Errorf("%s: no flags chain passes %s to Help", file, helpConst)
+		}
+		chain, chainPos = chains[0].flags, chains[0].pos
+	}
+	var msgs []string
+	for _, p := range Check(ParseHelp(help), chain) {
+		pos := p.Pos
+		if !pos.IsValid() {
+			pos = chainPos
+		}
+		msgs = append(msgs, fmt.Sprintf("%s: %s", fset.Position(pos), p.Message))
+	}
+	return msgs, nil
+}
+
+// Check compares the documented options with the registered
+// flags, the positions of problems are taken from flags
+func Check(options []*Option, flags []*Flag) []Problem {
+	var problems []Problem
+	report := func(pos token.Pos, format string, args ...interface{}) {
+		problems = append(problems, Problem{Pos: pos, Message: fmt.Sprintf(format, args...)})
+	}
+	matched := make(map[*Flag]bool)
+	for _, opt := range options {
+		flag := findFlag(flags, opt.Names)
+		display := strings.Join(opt.Names, ", ")
+		if flag == nil {
+			report(token.NoPos, "%s is documented in help but not registered", display)
+			continue
+		}
+		matched[flag] = true
+		if strings.Join(flag.Names, ",") != strings.Join(opt.Names, ",") {
+			report(flag.Pos, "%s: builder registers %s", display, strings.Join(flag.Names, ", "))
+		}
+		if !compatible(opt, flag.Method) {
+			report(flag.Pos, "%s: help implies %s but builder uses %s", display, opt.Method(), flag.Method)
+		}
+		if opt.Default != "" && opt.Default != flag.Default {
+			report(flag.Pos, "%s: help says default %s but builder has %q", display, opt.Default, flag.Default)
+		}
+		if len(opt.Env) > 0 && strings.Join(opt.Env, ",") != strings.Join(flag.Env, ",") {
+			report(flag.Pos, "%s: help says env %s but builder has %s", display, strings.Join(opt.Env, ", "), strings.Join(flag.Env, ", "))
+		}
+		if opt.Required && !flag.Required {
+			report(flag.Pos, "%s: help says required but builder does not", display)
+		}
+	}
+	for _, flag := range flags {
+		if !matched[flag] && !flag.Hidden {
+			report(flag.Pos, "%s is registered but not documented in help", strings.Join(flag.Names, ", "))
+		}
+	}
+	return problems
+}
+
+// compatible reports whether method can register opt. Help texts
+// carry less type information than the chain, so generic
+// placeholders accept any method taking a value.
+func compatible(opt *Option, method string) bool {
+	expect := opt.Method()
+	if method == expect || method == "Var" {
+		return true
+	}
+	switch expect {
+	case "Bool":
+		return method == "Count"
+	case "Help":
+		return method == "HelpFunc"
+	case "String":
+		return method != "Bool" && method != "Count" && method != "Help" && method != "HelpFunc"
+	case "StringSlice":
+		return method == "IntSlice" || method == "StringMap"
+	case "Int":
+		return method == "Count"
+	}
+	return false
+}
+
+func findFlag(flags []*Flag, names []string) *Flag {
+	for _, flag := range flags {
+		for _, name := range flag.Names {
+			for _, n := range names {
+				if name == n {
+					return flag
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Chain is a builder chain found in a Go file
+type Chain struct {
+	flags     []*Flag
+	helpConst string // identifier passed to Help, if any
+	pos       token.Pos
+}
+
+// Flags returns the flags registered by the chain
+func (c *Chain) Flags() []*Flag {
+	return c.flags
+}
+
+// FindChains returns the builder chains in f starting with a
+// call on the package pkg, like flags.String(...).Bool(...)
+func FindChains(f *ast.File, pkg string) []*Chain {
+	var chains []*Chain
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		calls, ok := chainCalls(call, pkg)
+		if !ok {
+			return true
+		}
+		chain := &Chain{pos: call.Pos()}
+		var last *Flag
+		for _, c := range calls {
+			sel := c.Fun.(*ast.SelectorExpr).Sel
+			method := sel.Name
+			if flagMethods[method] {
+				last = &Flag{Method: method, Hidden: method == "Removed", Pos: sel.Pos()}
+				if len(c.Args) > 0 {
+					if names, ok := stringLit(c.Args[0]); ok {
+						last.Names = splitNames(names)
+					}
+				}
+				if method == "Help" && len(c.Args) > 1 {
+					if ident, ok := c.Args[1].(*ast.Ident); ok {
+						chain.helpConst = ident.Name
+					}
+				}
+				chain.flags = append(chain.flags, last)
+				continue
+			}
+			if last == nil {
+				continue
+			}
+			switch method {
+			case "Default":
+				if len(c.Args) > 0 {
+					last.Default, _ = stringLit(c.Args[0])
+				}
+			case "Env":
+				for _, arg := range c.Args {
+					if env, ok := stringLit(arg); ok {
+						last.Env = append(last.Env, env)
+					}
+				}
+			case "Required":
+				last.Required = true
+			case "Hidden":
+				last.Hidden = true
+			}
+		}
+		if len(chain.flags) > 0 {
+			chains = append(chains, chain)
+		}
+		return false
+	})
+	return chains
+}
+
+// chainCalls unrolls a.B().C().D() into the calls B, C and D,
+// requiring the innermost receiver to be the package pkg
+func chainCalls(call *ast.CallExpr, pkg string) ([]*ast.CallExpr, bool) {
+	var calls []*ast.CallExpr
+	var expr ast.Expr = call
+	for {
+		c, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return nil, false
+		}
+		sel, ok := c.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return nil, false
+		}
+		calls = append(calls, c)
+		if ident, ok := sel.X.(*ast.Ident); ok {
+			if ident.Name != pkg {
+				return nil, false
+			}
+			break
+		}
+		expr = sel.X
+	}
+	for i, j := 0, len(calls)-1; i < j; i, j = i+1, j-1 {
+		calls[i], calls[j] = calls[j], calls[i]
+	}
+	return calls, true
+}
+
+// ReadString returns the string constant name declared in the Go file
+func ReadString(file string, name string) (string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		return "", err
+	}
+	s, ok := FindString(f, name)
+	if !ok {
+		return "", fmt.Errorf("%s: string constant %s not found", file, name)
+	}
+	return s, nil
+}
+
+// FindString returns the value of the string constant or
+// variable name declared at the top level of f
+func FindString(f *ast.File, name string) (string, bool) {
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || (genDecl.Tok != token.CONST && genDecl.Tok != token.VAR) {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, ident := range valueSpec.Names {
+				if ident.Name != name || i >= len(valueSpec.Values) {
+					continue
+				}
+				return stringLit(valueSpec.Values[i])
+			}
+		}
+	}
+	return "", false
+}
+
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+func splitNames(names string) []string {
+	var result []string
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
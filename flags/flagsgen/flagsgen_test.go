@@ -0,0 +1,183 @@
+package flagsgen
+
+import (
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xhd2015/less-gen/flags"
+)
+
+const testHelp = `
+Usage: myapp [options]
+
+Options:
+  --timeout DURATION  set timeout duration
+  --file FILE         add files to process, can be repeated
+  -v, --verbose       enable verbose output
+  -h, --help          show help
+`
+
+func TestParseHelp(t *testing.T) {
+	options := ParseHelp(testHelp)
+	var got [][]string
+	for _, opt := range options {
+		got = append(got, []string{strings.Join(opt.Names, ","), opt.Placeholder, opt.Method(), opt.VarName()})
+	}
+	expected := [][]string{
+		{"--timeout", "DURATION", "Duration", "timeout"},
+		{"--file", "FILE", "StringSlice", "files"},
+		{"-v,--verbose", "", "Bool", "verbose"},
+		{"-h,--help", "", "Help", "help"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Unexpected options:\n%v\nexpected:\n%v", got, expected)
+	}
+}
+
+func TestParseHelpAnnotations(t *testing.T) {
+	options := ParseHelp(`
+  --port=PORT       listen port (env: $APP_PORT, $PORT, default: 8080)
+  --level <LEVEL>   log level (one of: debug, info) (required)
+  --dry-run         print only
+`)
+	if len(options) != 3 {
+		t.Fatalf("Expected 3 options, got: %d", len(options))
+	}
+	port := options[0]
+	if port.Placeholder != "PORT" || port.Default != "8080" || !reflect.DeepEqual(port.Env, []string{"APP_PORT", "PORT"}) || port.Description != "listen port" {
+		t.Errorf("Unexpected port option: %+v", port)
+	}
+	level := options[1]
+	if !level.Required || !reflect.DeepEqual(level.Choices, []string{"debug", "info"}) || level.Method() != "Enum" {
+		t.Errorf("Unexpected level option: %+v", level)
+	}
+	if options[2].VarName() != "dryRun" {
+		t.Errorf("Unexpected var name: %s", options[2].VarName())
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	code := Generate(ParseHelp(testHelp), Config{Args: "os.Args[1:]"})
+	expected := `var timeout time.Duration
+var files []string
+var verbose bool
+
+remainArgs, err := flags.Duration("--timeout", &timeout).
+	StringSlice("--file", &files).
+	Bool("-v,--verbose", &verbose).
+	Help("-h,--help", help).
+	Parse(os.Args[1:])
+if err != nil {
+	return err
+}
+`
+	if code != expected {
+		t.Errorf("Unexpected code:\n%s\nexpected:\n%s", code, expected)
+	}
+}
+
+func TestGenerate_ReservedNames(t *testing.T) {
+	options := []*Option{
+		{Names: []string{"--args"}, Placeholder: "STRING"},
+		{Names: []string{"--err"}, Placeholder: "STRING"},
+		{Names: []string{"--flags"}, Placeholder: "STRING"},
+		{Names: []string{"--remain-args"}, Placeholder: "STRING"},
+		{Names: []string{"--help-text"}, Placeholder: "STRING"},
+	}
+	code := Generate(options, Config{HelpConst: "helpText"})
+	for _, expected := range []string{
+		"var args2 string\n",
+		"var err2 string\n",
+		"var flags2 string\n",
+		"var remainArgs2 string\n",
+		"var helpText2 string\n",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected %q in code:\n%s", expected, code)
+		}
+	}
+}
+
+// TestGenerateUsageRoundTrip parses the help generated by flags
+// and expects the same chain back
+func TestGenerateUsageRoundTrip(t *testing.T) {
+	var port int
+	var timeout time.Duration
+	var level string
+	usage := flags.Int("-p,--port", &port).Env("APP_PORT").Default("8080").
+		Duration("--timeout", &timeout).Desc("timeout").
+		Enum("--level", &level, "debug", "info").Required().
+		Help("-h,--help", "").
+		Usage()
+
+	code := Generate(ParseHelp(usage), Config{})
+	for _, expected := range []string{
+		`flags.Int("-p,--port", &port).Env("APP_PORT").Default("8080").`,
+		`Duration("--timeout", &timeout).`,
+		`Enum("--level", &level, "debug", "info").Required().`,
+		`Help("-h,--help", help).`,
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected %s in:\n%s", expected, code)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	src := "package main\n\nconst help = `" + testHelp + "  --port INT          port (default: 8080)\n`" + `
+
+func handle(args []string) error {
+	_, err := flags.String("--timeout", &timeout).
+		StringSlice("--files", &files).
+		Bool("-v,--verbose", &verbose).
+		Int("--port", &port).Default("80").
+		Help("-h,--help", help).
+		Parse(args)
+	return err
+}
+`
+	f, err := parser.ParseFile(token.NewFileSet(), "main.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	help, ok := FindString(f, "help")
+	if !ok {
+		t.Fatalf("Expected help constant")
+	}
+	chains := FindChains(f, "flags")
+	if len(chains) != 1 || len(chains[0].Flags()) != 5 {
+		t.Fatalf("Expected one chain with 5 flags, got: %v", chains)
+	}
+
+	var msgs []string
+	for _, p := range Check(ParseHelp(help), chains[0].Flags()) {
+		msgs = append(msgs, p.Message)
+	}
+	expected := []string{
+		"--timeout: help implies Duration but builder uses String",
+		"--file is documented in help but not registered",
+		`--port: help says default 8080 but builder has "80"`,
+		"--files is registered but not documented in help",
+	}
+	if !reflect.DeepEqual(msgs, expected) {
+		t.Errorf("Unexpected problems:\n%s\nexpected:\n%s", strings.Join(msgs, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestCheckAgrees(t *testing.T) {
+	options := ParseHelp(testHelp)
+	chain := []*Flag{
+		{Names: []string{"--timeout"}, Method: "Duration"},
+		{Names: []string{"--file"}, Method: "StringSlice"},
+		{Names: []string{"-v", "--verbose"}, Method: "Count"},
+		{Names: []string{"-h", "--help"}, Method: "HelpFunc"},
+		{Names: []string{"--debug"}, Method: "Bool", Hidden: true},
+	}
+	if problems := Check(options, chain); len(problems) > 0 {
+		t.Errorf("Expected no problems, got: %v", problems)
+	}
+}
@@ -0,0 +1,106 @@
+package flagsgen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Config controls the generated code
+type Config struct {
+	HelpConst string // name of the help text constant, defaults to help
+	Args      string // expression of the args to parse, defaults to args
+}
+
+// Generate returns the variable declarations and the builder chain
+// parsing options, like:
+//
+//	var timeout time.Duration
+//	var verbose bool
+//
+//	remainArgs, err := flags.Duration("--timeout", &timeout).
+//		Bool("-v,--verbose", &verbose).
+//		Help("-h,--help", help).
+//		Parse(args)
+//	if err != nil {
+//		return err
+//	}
+func Generate(options []*Option, cfg Config) string {
+	helpConst := cfg.HelpConst
+	if helpConst == "" {
+		helpConst = "help"
+	}
+	args := cfg.Args
+	if args == "" {
+		args = "args"
+	}
+
+	var sb strings.Builder
+	// the names the chain itself uses are taken too
+	vars := map[string]bool{
+		"args":       true,
+		"err":        true,
+		"remainArgs": true,
+		"flags":      true,
+		helpConst:    true,
+	}
+	var declared bool
+	varNames := make([]string, len(options))
+	for i, opt := range options {
+		if opt.IsHelp() {
+			continue
+		}
+		name := opt.VarName()
+		for n := 2; vars[name]; n++ {
+			name = fmt.Sprintf("%s%d", opt.VarName(), n)
+		}
+		vars[name] = true
+		varNames[i] = name
+		declared = true
+		fmt.Fprintf(&sb, "var %s %s\n", name, opt.GoType())
+	}
+	if declared {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("remainArgs, err := flags.")
+	for i, opt := range options {
+		if i > 0 {
+			sb.WriteString(".\n\t")
+		}
+		names := strconv.Quote(strings.Join(opt.Names, ","))
+		method := opt.Method()
+		switch method {
+		case "Help":
+			fmt.Fprintf(&sb, "Help(%s, %s)", names, helpConst)
+			continue
+		case "Enum":
+			fmt.Fprintf(&sb, "Enum(%s, &%s", names, varNames[i])
+			for _, choice := range opt.Choices {
+				fmt.Fprintf(&sb, ", %s", strconv.Quote(choice))
+			}
+			sb.WriteString(")")
+		default:
+			fmt.Fprintf(&sb, "%s(%s, &%s)", method, names, varNames[i])
+		}
+		if len(opt.Env) > 0 {
+			quoted := make([]string, 0, len(opt.Env))
+			for _, env := range opt.Env {
+				quoted = append(quoted, strconv.Quote(env))
+			}
+			fmt.Fprintf(&sb, ".Env(%s)", strings.Join(quoted, ", "))
+		}
+		if opt.Default != "" {
+			fmt.Fprintf(&sb, ".Default(%s)", strconv.Quote(opt.Default))
+		}
+		if opt.Required {
+			sb.WriteString(".Required()")
+		}
+	}
+	if len(options) == 0 {
+		sb.WriteString("New()")
+	}
+	fmt.Fprintf(&sb, ".\n\tParse(%s)\n", args)
+	sb.WriteString("if err != nil {\n\treturn err\n}\n")
+	return sb.String()
+}
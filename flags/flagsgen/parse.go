@@ -0,0 +1,191 @@
+// Package flagsgen generates flags builder chains from help texts,
+// and checks that a help text agrees with its builder chain.
+package flagsgen
+
+import (
+	"go/token"
+	"regexp"
+	"strings"
+
+	"github.com/xhd2015/less-gen/strcase"
+)
+
+// Option is a flag documented in a help text
+type Option struct {
+	Names       []string // like ["-v", "--verbose"]
+	Placeholder string   // value name like DURATION, empty for bool flags
+	Description string   // description without the annotations below
+	Default     string   // from (default: x)
+	Env         []string // from (env: $A, $B)
+	Required    bool     // from (required)
+	Choices     []string // from (one of: a, b)
+	Repeated    bool     // the description says the flag can be repeated
+}
+
+// ParseHelp extracts the options from a help text. Every line
+// starting with a dash is an option row like
+//
+//	-t, --timeout DURATION  set timeout duration (default: 30s)
+//
+// where the names and the description are separated by at
+// least two spaces. Other lines are ignored.
+func ParseHelp(help string) []*Option {
+	var options []*Option
+	for _, line := range strings.Split(help, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "-") || strings.Trim(line, "-") == "" {
+			continue
+		}
+		head, desc := line, ""
+		if loc := columnSep.FindStringIndex(line); loc != nil {
+			head, desc = line[:loc[0]], line[loc[1]:]
+		}
+		opt := &Option{}
+		for _, token := range strings.FieldsFunc(head, func(r rune) bool { return r == ',' || r == ' ' }) {
+			if !strings.HasPrefix(token, "-") {
+				if opt.Placeholder == "" {
+					opt.Placeholder = trimPlaceholder(token)
+				}
+				continue
+			}
+			name := token
+			if idx := strings.IndexAny(token, "=["); idx > 0 {
+				name = token[:idx]
+				if opt.Placeholder == "" {
+					opt.Placeholder = trimPlaceholder(token[idx:])
+				}
+			}
+			opt.Names = append(opt.Names, name)
+		}
+		opt.parseDescription(desc)
+		options = append(options, opt)
+	}
+	return options
+}
+
+var columnSep = regexp.MustCompile(`\s{2,}|\t`)
+
+var annotation = regexp.MustCompile(`\s*\(([^()]*)\)`)
+
+func trimPlaceholder(s string) string {
+	return strings.Trim(s, "=[]<>")
+}
+
+// parseDescription sets the description, moving the annotations
+// rendered by flags.Builder.Usage into their fields
+func (o *Option) parseDescription(desc string) {
+	desc = annotation.ReplaceAllStringFunc(desc, func(s string) string {
+		content := annotation.FindStringSubmatch(s)[1]
+		switch {
+		case content == "required":
+			o.Required = true
+		case strings.HasPrefix(content, "one of: "):
+			o.Choices = strings.Split(strings.TrimPrefix(content, "one of: "), ", ")
+		case strings.HasPrefix(content, "env: ") || strings.HasPrefix(content, "default: "):
+			if idx := strings.Index(content, "default: "); idx >= 0 {
+				o.Default = content[idx+len("default: "):]
+				content = strings.TrimSuffix(strings.TrimSpace(content[:idx]), ",")
+			}
+			for _, env := range strings.Split(strings.TrimPrefix(content, "env: "), ", ") {
+				if env = strings.TrimPrefix(strings.TrimSpace(env), "$"); env != "" {
+					o.Env = append(o.Env, env)
+				}
+			}
+		default:
+			return s
+		}
+		return ""
+	})
+	o.Description = strings.TrimSpace(desc)
+	lower := strings.ToLower(o.Description)
+	o.Repeated = strings.Contains(lower, "can be repeated") || strings.Contains(lower, "repeatable") ||
+		strings.HasSuffix(o.Placeholder, "...")
+	o.Placeholder = strings.TrimSuffix(o.Placeholder, "...")
+}
+
+// IsHelp reports whether the option is the help flag
+func (o *Option) IsHelp() bool {
+	if o.Placeholder != "" {
+		return false
+	}
+	for _, name := range o.Names {
+		if name == "-h" || name == "--help" {
+			return true
+		}
+	}
+	return false
+}
+
+// Method returns the builder method registering the option,
+// inferred from its placeholder and annotations
+func (o *Option) Method() string {
+	if o.IsHelp() {
+		return "Help"
+	}
+	if len(o.Choices) > 0 {
+		return "Enum"
+	}
+	switch strings.ToUpper(o.Placeholder) {
+	case "":
+		return "Bool"
+	case "DURATION":
+		return "Duration"
+	case "INT", "N", "NUM", "COUNT", "PORT", "SIZE":
+		if o.Repeated {
+			return "IntSlice"
+		}
+		return "Int"
+	case "FLOAT":
+		return "Float"
+	case "TIME", "DATE":
+		return "Time"
+	case "KEY=VALUE":
+		return "StringMap"
+	}
+	if o.Repeated {
+		return "StringSlice"
+	}
+	return "String"
+}
+
+// GoType returns the type of the variable receiving the option
+func (o *Option) GoType() string {
+	return goTypes[o.Method()]
+}
+
+var goTypes = map[string]string{
+	"Bool":        "bool",
+	"Count":       "int",
+	"Duration":    "time.Duration",
+	"Int":         "int",
+	"IntSlice":    "[]int",
+	"Float":       "float64",
+	"Time":        "time.Time",
+	"StringMap":   "map[string]string",
+	"StringSlice": "[]string",
+	"String":      "string",
+	"Enum":        "string",
+}
+
+// VarName returns the variable name derived from the longest
+// flag name, like dryRun for --dry-run
+func (o *Option) VarName() string {
+	var longest string
+	for _, name := range o.Names {
+		name = strings.TrimLeft(name, "-")
+		if len(name) > len(longest) {
+			longest = name
+		}
+	}
+	name := strcase.Decapitalize(strcase.SnakeToCamel(strings.NewReplacer("-", "_", ".", "_").Replace(longest)))
+	if o.Repeated && !strings.HasSuffix(name, "s") {
+		name += "s"
+	}
+	if token.IsKeyword(name) {
+		return name + "Flag"
+	}
+	if !token.IsIdentifier(name) {
+		return "flag" + strcase.Capitalize(name)
+	}
+	return name
+}
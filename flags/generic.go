@@ -0,0 +1,41 @@
+package flags
+
+import (
+	"reflect"
+	"time"
+)
+
+// Type is the set of target types accepted by Add. Named types
+// are accepted by their underlying type, except that only
+// time.Duration itself is parsed as a duration.
+type Type interface {
+	~bool | ~string |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64 |
+		time.Time | ~[]string | ~[]int | ~map[string]string
+}
+
+// Add adds a flag to b with the type inferred from target,
+// unsupported target types are compile errors instead of
+// Parse errors. It returns b for the modifiers like Desc.
+//
+//	b := flags.New()
+//	flags.Add(b, "--timeout", &timeout).Default("30s") // time.Duration
+//	flags.Add(b, "--file", &files)                     // []string
+//	remainArgs, err := b.Parse(args)
+func Add[T Type](b *Builder, names string, target *T) *Builder {
+	return b.add(names, reflect.TypeOf(target).Elem(), target)
+}
+
+// AddPtr is like Add for a **T target, whose inner pointer stays
+// nil unless the flag is set, e.g. a *bool telling unset from false
+func AddPtr[T Type](b *Builder, names string, target **T) *Builder {
+	return b.add(names, reflect.TypeOf(target).Elem().Elem(), target)
+}
+
+func (b *Builder) add(names string, t reflect.Type, target interface{}) *Builder {
+	flagType, _ := flagTypeOf(t) // always ok by the Type constraint
+	b.addFlag(parseNames(names), flagType, target)
+	return b
+}
@@ -0,0 +1,74 @@
+package flags
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testMode string
+
+func TestAdd(t *testing.T) {
+	var timeout time.Duration
+	var port uint16
+	var ratio float32
+	var verbose bool
+	var mode testMode
+	var files []string
+	var ids []int
+	var labels map[string]string
+	var since time.Time
+
+	b := New()
+	Add(b, "--timeout", &timeout).Default("30s")
+	Add(b, "--port", &port)
+	Add(b, "--ratio", &ratio)
+	Add(b, "-v,--verbose", &verbose)
+	Add(b, "--mode", &mode)
+	Add(b, "--file", &files)
+	Add(b, "--id", &ids)
+	Add(b, "--label", &labels)
+	Add(b, "--since", &since)
+	args, err := b.Parse([]string{
+		"--port", "8080", "--ratio", "0.5", "-v", "--mode", "debug",
+		"--file", "a", "--file", "b", "--id", "1", "--label", "k=v", "--since", "2024-01-02", "rest",
+	})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if timeout != 30*time.Second || port != 8080 || ratio != 0.5 || !verbose || mode != "debug" {
+		t.Errorf("Unexpected values: %v %v %v %v %v", timeout, port, ratio, verbose, mode)
+	}
+	if !reflect.DeepEqual(files, []string{"a", "b"}) || !reflect.DeepEqual(ids, []int{1}) || labels["k"] != "v" {
+		t.Errorf("Unexpected values: %v %v %v", files, ids, labels)
+	}
+	if !since.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected since: %v", since)
+	}
+	if !reflect.DeepEqual(args, []string{"rest"}) {
+		t.Errorf("Unexpected args: %v", args)
+	}
+
+	_, err = b.Parse([]string{"--port", "70000"})
+	if err == nil {
+		t.Errorf("Expected out of range error for uint16")
+	}
+}
+
+func TestAddPtr(t *testing.T) {
+	var color *bool
+	var name *string
+	b := New()
+	AddPtr(b, "--color", &color)
+	AddPtr(b, "--name", &name)
+	_, err := b.Parse([]string{"--no-color"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if color == nil || *color {
+		t.Errorf("Expected color=false")
+	}
+	if name != nil {
+		t.Errorf("Expected name to stay nil, got %q", *name)
+	}
+}
@@ -0,0 +1,228 @@
+package flags
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Name sets the program or command name shown in the generated help.
+// The root builder defaults to the base name of os.Args[0].
+func (b *Builder) Name(name string) *Builder {
+	b.name = name
+	return b
+}
+
+// Description sets the description of the program or command.
+// The first line is also used when listing subcommands.
+func (b *Builder) Description(description string) *Builder {
+	b.description = description
+	return b
+}
+
+// Desc sets the description of the last added flag
+func (b *Builder) Desc(description string) *Builder {
+	b.lastSpec("Desc").Description = description
+	return b
+}
+
+// Placeholder sets the value name of the last added flag,
+// e.g. FILE in `--file FILE`
+func (b *Builder) Placeholder(placeholder string) *Builder {
+	b.lastSpec("Placeholder").Placeholder = placeholder
+	return b
+}
+
+// Default sets the default value of the last added flag. The value
+// is parsed like a command line value and applied when the flag is
+// absent, and it is shown in the generated help.
+func (b *Builder) Default(value string) *Builder {
+	b.lastSpec("Default").Default = value
+	return b
+}
+
+// lastSpec returns the most recently added flag, which the
+// modifier methods like Desc and Default apply to. Without any
+// flag the error is returned by Parse, and the modifier applies
+// to a spec nothing refers to.
+func (b *Builder) lastSpec(method string) *FlagSpec {
+	if len(b.flagSpecs) == 0 {
+		b.setErr(fmt.Errorf("%s called before adding any flag", method))
+		return &FlagSpec{}
+	}
+	return &b.flagSpecs[len(b.flagSpecs)-1]
+}
+
+// Usage renders the help page from the registered commands and flags:
+//
+//	Usage: myapp [OPTIONS]
+//
+//	Options:
+//	  --timeout DURATION  set timeout duration (default: 30s)
+//	  -v, --verbose       enable verbose output
+//	  -h, --help          show help message
+func (b *Builder) Usage() string {
+	var sb strings.Builder
+	if b.description != "" {
+		sb.WriteString(strings.TrimSpace(b.description))
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString("Usage: " + b.usageLine())
+	sb.WriteString("\n")
+
+	if len(b.commands) > 0 {
+		rows := make([][2]string, 0, len(b.commands))
+		for _, cmd := range b.commands {
+			rows = append(rows, [2]string{cmd.name, firstLine(cmd.description)})
+		}
+		sb.WriteString("\nCommands:\n")
+		writeRows(&sb, rows)
+	}
+
+	if rows := b.flagRows(b.flagSpecs, nil); len(rows) > 0 {
+		sb.WriteString("\nOptions:\n")
+		writeRows(&sb, rows)
+	}
+
+	var globalRows [][2]string
+	for p := b.parent; p != nil; p = p.parent {
+		globalRows = append(globalRows, b.flagRows(p.flagSpecs, b)...)
+	}
+	if len(globalRows) > 0 {
+		sb.WriteString("\nGlobal Options:\n")
+		writeRows(&sb, globalRows)
+	}
+	return sb.String()
+}
+
+// usageLine returns the synopsis like myapp <command> [OPTIONS] PKG
+func (b *Builder) usageLine() string {
+	usage := b.programPath()
+	if len(b.commands) > 0 {
+		usage += " <command>"
+	}
+	usage += " [OPTIONS]"
+	if len(b.argSpecs) > 0 {
+		usage += " " + b.argsUsage()
+	}
+	return usage
+}
+
+// flagRows formats specs into name and description columns,
+// skipping inherited flags whose names are shadowed in cmd
+func (b *Builder) flagRows(specs []FlagSpec, cmd *Builder) [][2]string {
+	rows := make([][2]string, 0, len(specs))
+	for i := range specs {
+		spec := &specs[i]
+		if spec.hidden() || cmd != nil && cmd.findFlagSpec(spec.Names[0]) != spec {
+			continue
+		}
+		rows = append(rows, [2]string{flagUsageName(spec), flagUsageDesc(spec)})
+	}
+	return rows
+}
+
+func flagUsageName(spec *FlagSpec) string {
+	name := strings.Join(spec.Names, ", ")
+	if spec.isBool() {
+		return name
+	}
+	return name + " " + flagPlaceholder(spec)
+}
+
+func flagPlaceholder(spec *FlagSpec) string {
+	if spec.Placeholder != "" {
+		return spec.Placeholder
+	}
+	return defaultPlaceholder(spec)
+}
+
+// flagUsageDesc appends the fallback sources to the description
+// in the order they are consulted, e.g. (env: $PORT, default: 80)
+func flagUsageDesc(spec *FlagSpec) string {
+	desc := spec.Description
+	if cv, ok := spec.Value.(choicesValue); ok {
+		desc = strings.TrimSpace(fmt.Sprintf("%s (one of: %s)", desc, strings.Join(cv.Choices(), ", ")))
+	}
+	if spec.Required {
+		desc = strings.TrimSpace(desc + " (required)")
+	}
+	var fallbacks []string
+	if len(spec.EnvVars) > 0 {
+		envs := make([]string, 0, len(spec.EnvVars))
+		for _, env := range spec.EnvVars {
+			envs = append(envs, "$"+env)
+		}
+		fallbacks = append(fallbacks, "env: "+strings.Join(envs, ", "))
+	}
+	if spec.Default != "" {
+		fallbacks = append(fallbacks, "default: "+spec.Default)
+	}
+	if len(fallbacks) == 0 {
+		return desc
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s (%s)", desc, strings.Join(fallbacks, ", ")))
+}
+
+func defaultPlaceholder(spec *FlagSpec) string {
+	switch spec.Type {
+	case FlagTypeDuration:
+		return "DURATION"
+	case FlagTypeInt, FlagTypeIntSlice:
+		return "INT"
+	case FlagTypeFloat:
+		return "FLOAT"
+	case FlagTypeStringMap:
+		return "KEY=VALUE"
+	case FlagTypeTime:
+		return "TIME"
+	case FlagTypeValue:
+		if spec.Value != nil && spec.Value.Type() != "" {
+			return strings.ToUpper(spec.Value.Type())
+		}
+		return "VALUE"
+	default:
+		return "STRING"
+	}
+}
+
+// programPath returns the program name followed by the subcommand names
+func (b *Builder) programPath() string {
+	root := b.root()
+	path := b.commandPath()
+	if root.name != "" {
+		return path
+	}
+	prog := filepath.Base(os.Args[0])
+	if path == "" {
+		return prog
+	}
+	return prog + " " + path
+}
+
+// writeRows writes two aligned columns, indented by two spaces
+func writeRows(sb *strings.Builder, rows [][2]string) {
+	width := 0
+	for _, row := range rows {
+		if len(row[0]) > width {
+			width = len(row[0])
+		}
+	}
+	for _, row := range rows {
+		if row[1] == "" {
+			fmt.Fprintf(sb, "  %s\n", row[0])
+			continue
+		}
+		fmt.Fprintf(sb, "  %-*s  %s\n", width, row[0], row[1])
+	}
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if idx := strings.Index(s, "\n"); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
@@ -0,0 +1,115 @@
+package flags
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsage(t *testing.T) {
+	var timeout time.Duration
+	var files []string
+	var verbose bool
+
+	b := Duration("--timeout", &timeout).Desc("set timeout duration").Default("30s").
+		StringSlice("--file", &files).Placeholder("FILE").Desc("add files to process, can be repeated").
+		Bool("-v,--verbose", &verbose).Desc("enable verbose output").
+		Help("-h,--help", "").
+		Name("myapp")
+
+	expect := `Usage: myapp [OPTIONS]
+
+Options:
+  --timeout DURATION  set timeout duration (default: 30s)
+  --file FILE         add files to process, can be repeated
+  -v, --verbose       enable verbose output
+  -h, --help          show help message
+`
+	if got := b.Usage(); got != expect {
+		t.Errorf("Usage() mismatch, expect:\n%s\ngot:\n%s", expect, got)
+	}
+}
+
+func TestUsage_Commands(t *testing.T) {
+	var verbose bool
+	var pkg string
+	var gen *Builder
+
+	b := New().Name("tool").
+		Description("tool generates code").
+		Bool("-v,--verbose", &verbose).Desc("enable verbose output").
+		Help("-h,--help", "").
+		Command("gen", func(c *Builder) {
+			gen = c
+			c.Description("generate api code\nmore details").
+				String("--pkg", &pkg).Desc("package to generate")
+		}).
+		Command("clean", func(c *Builder) {})
+
+	expect := `tool generates code
+
+Usage: tool <command> [OPTIONS]
+
+Commands:
+  gen    generate api code
+  clean
+
+Options:
+  -v, --verbose  enable verbose output
+  -h, --help     show help message
+`
+	if got := b.Usage(); got != expect {
+		t.Errorf("Usage() mismatch, expect:\n%s\ngot:\n%s", expect, got)
+	}
+
+	expectGen := `generate api code
+more details
+
+Usage: tool gen [OPTIONS]
+
+Options:
+  --pkg STRING  package to generate
+
+Global Options:
+  -v, --verbose  enable verbose output
+  -h, --help     show help message
+`
+	if got := gen.Usage(); got != expectGen {
+		t.Errorf("Usage() mismatch, expect:\n%s\ngot:\n%s", expectGen, got)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	var timeout time.Duration
+	var port int
+
+	_, err := Duration("--timeout", &timeout).Default("30s").
+		Int("--port", &port).Default("8080").
+		Parse([]string{"--port", "9090"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if timeout != 30*time.Second {
+		t.Errorf("Expected default timeout=30s, got %v", timeout)
+	}
+	if port != 9090 {
+		t.Errorf("Expected port=9090, got %d", port)
+	}
+}
+
+func TestDefault_Invalid(t *testing.T) {
+	var port int
+	_, err := Int("--port", &port).Default("abc").Parse(nil)
+	if err == nil {
+		t.Fatal("Expected error for invalid default")
+	}
+}
+
+func TestModifierWithoutFlag(t *testing.T) {
+	var verbose bool
+	_, err := New().Desc("verbose output").Required().
+		Bool("-v", &verbose).
+		Parse([]string{"-v"})
+	if err == nil || err.Error() != "Desc called before adding any flag" {
+		t.Errorf("Expected Desc error, got: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package flags
+
+import (
+	"io"
+	"os"
+)
+
+// Stdout sets the writer for help and completion output,
+// defaults to os.Stdout
+func (b *Builder) Stdout(w io.Writer) *Builder {
+	b.stdout = w
+	return b
+}
+
+// Stderr sets the writer for diagnostics, defaults to os.Stderr
+func (b *Builder) Stderr(w io.Writer) *Builder {
+	b.stderr = w
+	return b
+}
+
+// Exit sets the function called to exit after help or completion
+// is printed, defaults to os.Exit. If fn returns, Parse returns
+// ErrHelp or ErrComplete. It has no effect when HelpNoExit is set.
+func (b *Builder) Exit(fn func(code int)) *Builder {
+	b.exit = fn
+	return b
+}
+
+// outWriter returns the stdout writer configured on the root builder
+func (b *Builder) outWriter() io.Writer {
+	if w := b.root().stdout; w != nil {
+		return w
+	}
+	return os.Stdout
+}
+
+// errWriter returns the stderr writer configured on the root builder
+func (b *Builder) errWriter() io.Writer {
+	if w := b.root().stderr; w != nil {
+		return w
+	}
+	return os.Stderr
+}
+
+// exitNow exits with code unless HelpNoExit is set
+func (b *Builder) exitNow(code int) {
+	r := b.root()
+	if r.helpNoExit {
+		return
+	}
+	if r.exit != nil {
+		r.exit(code)
+		return
+	}
+	os.Exit(code)
+}
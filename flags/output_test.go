@@ -0,0 +1,93 @@
+package flags
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHelpWriterAndExit(t *testing.T) {
+	var out strings.Builder
+	var code = -1
+	var verbose bool
+	_, err := Bool("-v,--verbose", &verbose).Desc("verbose output").
+		Help("-h,--help", "").
+		Name("app").
+		Stdout(&out).
+		Exit(func(c int) { code = c }).
+		Parse([]string{"--help"})
+	if !errors.Is(err, ErrHelp) {
+		t.Fatalf("Expected ErrHelp, got: %v", err)
+	}
+	if code != 0 {
+		t.Errorf("Expected exit code 0, got: %d", code)
+	}
+	if !strings.Contains(out.String(), "Usage: app [OPTIONS]") || !strings.Contains(out.String(), "verbose output") {
+		t.Errorf("Unexpected help output: %q", out.String())
+	}
+}
+
+func TestHelpNoExitSkipsExitHook(t *testing.T) {
+	var out strings.Builder
+	called := false
+	_, err := Help("-h", "usage: app").
+		HelpNoExit().
+		Stdout(&out).
+		Exit(func(int) { called = true }).
+		Parse([]string{"-h"})
+	if !errors.Is(err, ErrHelp) {
+		t.Fatalf("Expected ErrHelp, got: %v", err)
+	}
+	if called {
+		t.Errorf("Expected exit hook not to be called")
+	}
+	if out.String() != "usage: app\n" {
+		t.Errorf("Unexpected help output: %q", out.String())
+	}
+}
+
+func TestSubcommandHelpUsesRootWriter(t *testing.T) {
+	var out strings.Builder
+	_, err := New().Name("app").
+		Stdout(&out).
+		Exit(func(int) {}).
+		Command("build", func(c *Builder) {
+			c.Description("build the project").Help("-h,--help", "")
+		}).
+		Parse([]string{"build", "-h"})
+	if !errors.Is(err, ErrHelp) {
+		t.Fatalf("Expected ErrHelp, got: %v", err)
+	}
+	if !strings.Contains(out.String(), "Usage: app build [OPTIONS]") {
+		t.Errorf("Unexpected help output: %q", out.String())
+	}
+}
+
+func TestCompletionWriter(t *testing.T) {
+	var out strings.Builder
+	var verbose bool
+	_, err := Bool("--verbose", &verbose).
+		Stdout(&out).
+		Exit(func(int) {}).
+		Parse([]string{"__complete", "--v"})
+	if !errors.Is(err, ErrComplete) {
+		t.Fatalf("Expected ErrComplete, got: %v", err)
+	}
+	if out.String() != "--verbose\n:nofiles\n" {
+		t.Errorf("Unexpected completion output: %q", out.String())
+	}
+}
+
+func TestCompletionCommandWriter(t *testing.T) {
+	var out strings.Builder
+	_, err := New().Name("app").
+		Stdout(&out).
+		CompletionCommand("completion").
+		Parse([]string{"completion", "bash"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(out.String(), "complete -o default -F _app_complete app") {
+		t.Errorf("Unexpected completion script: %q", out.String())
+	}
+}
@@ -0,0 +1,47 @@
+package flags
+
+// Passthrough keeps unknown flags in the remaining args, in their
+// original order, instead of failing, so wrapper commands can
+// forward them verbatim. valueFlags lists the unknown flags taking
+// the next arg as their value, like -o or -run of go test, other
+// unknown flags are kept as switches or in the -flag=value form.
+// A -- is kept too, the args after it stay positional.
+//
+//	remainArgs, err := flags.Bool("--dry-run", &dryRun).
+//		Passthrough("-o", "-run", "-tags").
+//		Parse(args) // go test -run TestX ./... keeps -run TestX ./...
+//
+// Unknown flags still fail inside POSIX short flag clusters.
+func (b *Builder) Passthrough(valueFlags ...string) *Builder {
+	b.passthrough = true
+	b.passthroughValues = append(b.passthroughValues, valueFlags...)
+	return b
+}
+
+// passthroughEnabled reports whether Passthrough is set on b or its ancestors
+func (b *Builder) passthroughEnabled() bool {
+	for c := b; c != nil; c = c.parent {
+		if c.passthrough {
+			return true
+		}
+	}
+	return false
+}
+
+// passthroughFlag reports whether the unknown flag is kept by
+// Passthrough of b or its ancestors, and whether it takes a value
+func (b *Builder) passthroughFlag(flag string) (bool, bool) {
+	passthrough := false
+	for c := b; c != nil; c = c.parent {
+		if !c.passthrough {
+			continue
+		}
+		passthrough = true
+		for _, name := range c.passthroughValues {
+			if name == flag {
+				return true, true
+			}
+		}
+	}
+	return passthrough, false
+}
@@ -0,0 +1,86 @@
+package flags
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPassthrough(t *testing.T) {
+	var dryRun bool
+	var pkg string
+	args, err := Bool("--dry-run", &dryRun).
+		Passthrough("-run", "-tags").
+		Parse([]string{"-v", "-run", "TestX", "--dry-run", "./...", "-count=1", "-tags=dev", "-race", "--", "-args", "x"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !dryRun {
+		t.Errorf("Expected dryRun=true")
+	}
+	expected := []string{"-v", "-run", "TestX", "./...", "-count=1", "-tags=dev", "-race", "--", "-args", "x"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("Expected args %v, got %v", expected, args)
+	}
+
+	// -- is kept for the wrapped command, but is not an argument
+	var out string
+	res, err := Bool("-x", &dryRun).
+		Passthrough().
+		Arg("OUT", &out).
+		ParseResult([]string{"-x", "--", "-y"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if out != "-y" || !reflect.DeepEqual(res.Args, []string{"--", "-y"}) {
+		t.Errorf("Expected OUT=-y and args [-- -y], got %q %v", out, res.Args)
+	}
+
+	// a value flag at the end has nothing to consume
+	args, err = String("--pkg", &pkg).Passthrough("-o").Parse([]string{"--pkg", "x", "-o"})
+	if err != nil || !reflect.DeepEqual(args, []string{"-o"}) {
+		t.Errorf("Expected [-o], got %v, %v", args, err)
+	}
+}
+
+func TestPassthroughDisabled(t *testing.T) {
+	_, err := New().Parse([]string{"-race"})
+	if err == nil || !strings.Contains(err.Error(), "unrecognized flag: -race") {
+		t.Errorf("Expected unrecognized flag error, got: %v", err)
+	}
+}
+
+func TestPassthroughCommand(t *testing.T) {
+	var verbose bool
+	var pkg string
+	var handled []string
+	_, err := Bool("-v", &verbose).
+		Command("test", func(c *Builder) {
+			c.Passthrough("-run").
+				Arg("PKG", &pkg).
+				Handle(func(args []string) error {
+					handled = args
+					return nil
+				})
+		}).
+		Parse([]string{"test", "-run", "TestX", "-v", "./flags", "-race"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !verbose || pkg != "./flags" {
+		t.Errorf("Expected verbose and pkg=./flags, got %v %q", verbose, pkg)
+	}
+	if !reflect.DeepEqual(handled, []string{"-run", "TestX", "./flags", "-race"}) {
+		t.Errorf("Unexpected handler args: %v", handled)
+	}
+
+	// unknown flags of the root are not passed through
+	_, err = Bool("-v", &verbose).
+		Command("test", func(c *Builder) {
+			c.Passthrough().Handle(func(args []string) error { return nil })
+		}).
+		Parse([]string{"-race", "test"})
+	if err == nil || !strings.Contains(err.Error(), "unrecognized flag: -race") {
+		t.Errorf("Expected unrecognized flag error, got: %v", err)
+	}
+}
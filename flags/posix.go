@@ -0,0 +1,75 @@
+package flags
+
+import "strings"
+
+// Count creates a new builder and adds a counter flag
+func Count(names string, target interface{}) *Builder {
+	return (&Builder{}).Count(names, target)
+}
+
+// Count adds a counter flag to the builder, each occurrence
+// increments the target, so -vvv in POSIX mode sets it to 3.
+// An explicit --verbose=2 sets the count directly.
+func (b *Builder) Count(names string, target interface{}) *Builder {
+	b.addFlag(parseNames(names), FlagTypeCount, target)
+	return b
+}
+
+// POSIX enables POSIX style short flags: single letter flags can
+// be clustered as in -xvf file, and short flags accept attached
+// values as in -p8080. Long flags like --name=value are unchanged.
+func (b *Builder) POSIX() *Builder {
+	b.posix = true
+	return b
+}
+
+// isShortCluster reports whether arg looks like -abc or -p8080
+func isShortCluster(arg string) bool {
+	return len(arg) > 2 && arg[0] == '-' && arg[1] != '-'
+}
+
+// parseShortCluster parses args[*i] as clustered short flags. A flag
+// taking a value consumes the rest of the cluster, or the next
+// argument if it is the last letter. The help flag is returned
+// instead of being handled.
+func (b *Builder) parseShortCluster(cur *Builder, args []string, i *int, state *parseState) (*FlagSpec, error) {
+	start := *i
+	cluster := args[*i][1:]
+	for j := 0; j < len(cluster); j++ {
+		flag := "-" + cluster[j:j+1]
+		spec := cur.findFlagSpec(flag)
+		if spec == nil {
+			return nil, unknownFlagError(cur, flag, start)
+		}
+		err := b.checkUsable(spec, flag, start, state)
+		if err != nil {
+			return nil, err
+		}
+		if spec.help {
+			return spec, nil
+		}
+		rest := cluster[j+1:]
+		var value string
+		if spec.isBool() {
+			if strings.HasPrefix(rest, "=") {
+				value = rest[1:]
+				j = len(cluster)
+			}
+		} else {
+			value = strings.TrimPrefix(rest, "=")
+			if value == "" {
+				if *i+1 >= len(args) {
+					return nil, &ParseError{Kind: KindMissingValue, Name: flag, Index: start}
+				}
+				*i++
+				value = args[*i]
+			}
+			j = len(cluster)
+		}
+		err = state.setFlag(spec, value, SourceCLI)
+		if err != nil {
+			return nil, &ParseError{Kind: KindInvalidValue, Name: flag, Index: start, Err: err}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,100 @@
+package flags
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPOSIX_Cluster(t *testing.T) {
+	var extract, verbose bool
+	var file string
+	remainArgs, err := Bool("-x", &extract).
+		Bool("-v,--verbose", &verbose).
+		String("-f,--file", &file).
+		POSIX().
+		Parse([]string{"-xvf", "archive.tar", "rest"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !extract || !verbose || file != "archive.tar" {
+		t.Errorf("Expected x, v and f=archive.tar, got x=%v v=%v f=%q", extract, verbose, file)
+	}
+	if len(remainArgs) != 1 || remainArgs[0] != "rest" {
+		t.Errorf("Expected remainArgs=['rest'], got %v", remainArgs)
+	}
+}
+
+func TestPOSIX_AttachedValue(t *testing.T) {
+	var port int
+	var verbose bool
+	_, err := Int("-p,--port", &port).Bool("-v", &verbose).POSIX().Parse([]string{"-vp8080"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if port != 8080 || !verbose {
+		t.Errorf("Expected port=8080 and verbose, got port=%d verbose=%v", port, verbose)
+	}
+
+	_, err = Int("-p,--port", &port).POSIX().Parse([]string{"-p=9090"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if port != 9090 {
+		t.Errorf("Expected port=9090, got %d", port)
+	}
+}
+
+func TestPOSIX_Count(t *testing.T) {
+	var verbosity int
+	_, err := Count("-v,--verbose", &verbosity).POSIX().Parse([]string{"-vvv", "--verbose"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if verbosity != 4 {
+		t.Errorf("Expected verbosity=4, got %d", verbosity)
+	}
+
+	verbosity = 0
+	_, err = Count("-v,--verbose", &verbosity).Parse([]string{"--verbose=2"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if verbosity != 2 {
+		t.Errorf("Expected verbosity=2, got %d", verbosity)
+	}
+}
+
+func TestPOSIX_LongNamesUnchanged(t *testing.T) {
+	var race bool
+	var name string
+	_, err := Bool("-race", &race).String("--name", &name).POSIX().Parse([]string{"-race", "--name=x"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !race || name != "x" {
+		t.Errorf("Expected race and name=x, got race=%v name=%q", race, name)
+	}
+}
+
+func TestPOSIX_Errors(t *testing.T) {
+	var verbose bool
+	var file string
+	b := Bool("-v", &verbose).String("-f", &file).POSIX()
+
+	_, err := b.Parse([]string{"-vz"})
+	if err == nil || !strings.Contains(err.Error(), "unrecognized flag: -z") {
+		t.Errorf("Expected unrecognized flag -z, got: %v", err)
+	}
+	_, err = b.Parse([]string{"-vf"})
+	if err == nil || !strings.Contains(err.Error(), "-f requires a value") {
+		t.Errorf("Expected missing value error, got: %v", err)
+	}
+}
+
+func TestPOSIX_Disabled(t *testing.T) {
+	var extract, verbose bool
+	_, err := Bool("-x", &extract).Bool("-v", &verbose).Parse([]string{"-xv"})
+	if err == nil || !strings.Contains(err.Error(), "unrecognized flag: -xv") {
+		t.Errorf("Expected clusters to be rejected without POSIX, got: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package flags
+
+// Source tells where the value of a flag came from
+type Source int
+
+const (
+	SourceNone    Source = iota // the flag was not set
+	SourceDefault               // the default value
+	SourceConfig                // the config file, see ConfigFile
+	SourceEnv                   // an environment variable
+	SourceCLI                   // the command line
+)
+
+var sourceNames = map[Source]string{
+	SourceNone:    "none",
+	SourceDefault: "default",
+	SourceConfig:  "config",
+	SourceEnv:     "env",
+	SourceCLI:     "cli",
+}
+
+func (s Source) String() string {
+	if name, ok := sourceNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseResult describes the outcome of ParseResult
+type ParseResult struct {
+	Args    []string // remaining args
+	Command string   // path of the selected subcommand, empty for the root
+	Help    bool     // whether help was requested
+
+	cmd     *Builder
+	sources map[*FlagSpec]Source
+}
+
+// Source returns where the value of the flag came from,
+// name can be any name of the flag
+func (r *ParseResult) Source(name string) Source {
+	if r.cmd == nil {
+		return SourceNone
+	}
+	spec := r.cmd.findFlagSpec(name)
+	if spec == nil {
+		spec = r.cmd.findNegatedSpec(name)
+	}
+	if spec == nil {
+		return SourceNone
+	}
+	return r.sources[spec]
+}
+
+// IsSet reports whether the flag was given on the command
+// line, through its environment variables or a config file
+func (r *ParseResult) IsSet(name string) bool {
+	s := r.Source(name)
+	return s != SourceNone && s != SourceDefault
+}
+
+// Set returns the display names of the flags given on the
+// command line, through their environment variables or a config file
+func (r *ParseResult) Set() []string {
+	var names []string
+	for c := r.cmd; c != nil; c = c.parent {
+		for i := range c.flagSpecs {
+			spec := &c.flagSpecs[i]
+			if s := r.sources[spec]; s != SourceNone && s != SourceDefault {
+				names = append(names, displayName(spec))
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,82 @@
+package flags
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestParseResultSources(t *testing.T) {
+	var host, mode string
+	var port int
+	var verbose bool
+	t.Setenv("TEST_RESULT_PORT", "9090")
+	b := String("--host", &host).Default("localhost").
+		Int("--port", &port).Env("TEST_RESULT_PORT").Default("8080").
+		Bool("-v,--verbose", &verbose).
+		String("--mode", &mode)
+
+	res, err := b.ParseResult([]string{"-v", "a", "b"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	tests := []struct {
+		name     string
+		expected Source
+	}{
+		{"--host", SourceDefault},
+		{"--port", SourceEnv},
+		{"-v", SourceCLI},
+		{"--verbose", SourceCLI},
+		{"--mode", SourceNone},
+		{"--unknown", SourceNone},
+	}
+	for _, tt := range tests {
+		if got := res.Source(tt.name); got != tt.expected {
+			t.Errorf("Source(%s) = %v, expected %v", tt.name, got, tt.expected)
+		}
+	}
+	if res.IsSet("--host") || !res.IsSet("--port") || !res.IsSet("--verbose") {
+		t.Errorf("Unexpected IsSet results")
+	}
+	if !reflect.DeepEqual(res.Set(), []string{"--port", "--verbose"}) {
+		t.Errorf("Unexpected set flags: %v", res.Set())
+	}
+	if !reflect.DeepEqual(res.Args, []string{"a", "b"}) || res.Help {
+		t.Errorf("Unexpected result: %+v", res)
+	}
+}
+
+func TestParseResultCommand(t *testing.T) {
+	var verbose bool
+	b := Bool("-v", &verbose).
+		Command("gen", func(c *Builder) {
+			c.Command("api", func(c *Builder) {
+				c.Handle(func(args []string) error { return nil })
+			})
+		})
+	res, err := b.ParseResult([]string{"gen", "api", "-v"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if res.Command != "gen api" || res.Source("-v") != SourceCLI {
+		t.Errorf("Unexpected result: %+v", res)
+	}
+}
+
+func TestParseResultHelp(t *testing.T) {
+	res, err := Help("-h", "usage").HelpNoExit().Stdout(io.Discard).ParseResult([]string{"-h"})
+	if !errors.Is(err, ErrHelp) {
+		t.Fatalf("Expected ErrHelp, got: %v", err)
+	}
+	if res == nil || !res.Help {
+		t.Errorf("Expected help result, got: %+v", res)
+	}
+
+	_, err = New().ParseResult([]string{"--bad"})
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Errorf("Expected *ParseError, got: %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package flags
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FromFlagSet creates a new builder with the flags of fs, see ImportFlagSet
+func FromFlagSet(fs *flag.FlagSet) *Builder {
+	return (&Builder{}).ImportFlagSet(fs)
+}
+
+// ImportFlagSet adds all flags defined in fs, like flag.CommandLine,
+// keeping their usage and defaults. A single letter flag x is added
+// as -x, others like name as both -name and --name, since the flag
+// package accepts both. Values set through the builder go to the
+// flag.Value of fs.
+func (b *Builder) ImportFlagSet(fs *flag.FlagSet) *Builder {
+	fs.VisitAll(func(f *flag.Flag) {
+		names := []string{"-" + f.Name}
+		if len(f.Name) > 1 {
+			names = append(names, "--"+f.Name)
+		}
+		placeholder, usage := flag.UnquoteUsage(f)
+		value := stdValue{f.Value}
+		spec := FlagSpec{
+			Names:       names,
+			Type:        FlagTypeValue,
+			Value:       value,
+			Description: usage,
+		}
+		if !value.IsBoolFlag() {
+			spec.Placeholder = strings.ToUpper(placeholder)
+		}
+		if value.basic() && !isZeroValue(f.DefValue) {
+			spec.Default = f.DefValue
+		}
+		b.flagSpecs = append(b.flagSpecs, spec)
+	})
+	return b
+}
+
+// stdValue adapts a flag.Value, which lacks Type
+type stdValue struct {
+	flag.Value
+}
+
+// Set passes true for a bool flag given without value,
+// as the flag package does
+func (v stdValue) Set(s string) error {
+	if s == "" && v.IsBoolFlag() {
+		s = "true"
+	}
+	return v.Value.Set(s)
+}
+
+func (v stdValue) Type() string {
+	getter, ok := v.Value.(flag.Getter)
+	if !ok {
+		return "value"
+	}
+	switch getter.Get().(type) {
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case int, int64, uint, uint64:
+		return "int"
+	case float64:
+		return "float"
+	case time.Duration:
+		return "duration"
+	}
+	return "value"
+}
+
+func (v stdValue) IsBoolFlag() bool {
+	bf, ok := v.Value.(boolFlag)
+	return ok && bf.IsBoolFlag()
+}
+
+// basic reports whether the value is one of the flag package's
+// own types, for which setting the default again is harmless
+func (v stdValue) basic() bool {
+	return v.Type() != "value"
+}
+
+func isZeroValue(s string) bool {
+	return s == "" || s == "false" || s == "0" || s == "0s"
+}
+
+// ExportFlagSet defines the flags of b on fs, like flag.CommandLine,
+// so packages built on the flag package parse into the same targets.
+// Names are stripped of dashes, help flags are skipped and default
+// values are applied to the targets right away, like flag.String
+// does. Environment variables are not consulted by fs. Nothing is
+// defined if a name is already defined on fs.
+func (b *Builder) ExportFlagSet(fs *flag.FlagSet) error {
+	names := make([][]string, len(b.flagSpecs))
+	defined := make(map[string]int)
+	for i := range b.flagSpecs {
+		spec := &b.flagSpecs[i]
+		if spec.help || spec.Value == nil {
+			continue
+		}
+		for _, name := range spec.Names {
+			// -name and --name are the same flag for fs
+			name = strings.TrimLeft(name, "-")
+			if j, ok := defined[name]; ok && j == i {
+				continue
+			}
+			if _, ok := defined[name]; ok || fs.Lookup(name) != nil {
+				return fmt.Errorf("flag %s already defined on %s", name, fs.Name())
+			}
+			defined[name] = i
+			names[i] = append(names[i], name)
+		}
+	}
+	for i := range b.flagSpecs {
+		spec := &b.flagSpecs[i]
+		if len(names[i]) == 0 {
+			continue
+		}
+		if spec.Default != "" {
+			err := setValue(spec, spec.Default)
+			if err != nil {
+				return fmt.Errorf("error setting default value for %s: %v", spec.Names[0], err)
+			}
+		}
+		for _, name := range names[i] {
+			fs.Var(exportedValue{spec}, name, spec.Description)
+		}
+	}
+	return nil
+}
+
+// exportedValue adapts a flag to flag.Value
+type exportedValue struct {
+	spec *FlagSpec
+}
+
+func (v exportedValue) Set(s string) error {
+	if v.spec.Type == FlagTypeCount && s == "true" {
+		// the flag package sets bool flags to true
+		s = ""
+	}
+	return setValue(v.spec, s)
+}
+
+func (v exportedValue) String() string {
+	if v.spec == nil || v.spec.Value == nil {
+		return ""
+	}
+	return v.spec.Value.String()
+}
+
+func (v exportedValue) IsBoolFlag() bool {
+	return v.spec != nil && v.spec.isBool()
+}
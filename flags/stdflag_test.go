@@ -0,0 +1,117 @@
+package flags
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportFlagSet(t *testing.T) {
+	fs := flag.NewFlagSet("old", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "enable verbose output")
+	timeout := fs.Duration("timeout", 30*time.Second, "request `timeout`")
+	name := fs.String("name", "", "user name")
+	port := fs.Int("port", 8080, "listen port")
+
+	b := FromFlagSet(fs).Name("old")
+	args, err := b.Parse([]string{"-v", "--timeout", "5s", "-name=x", "rest"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !*verbose || *timeout != 5*time.Second || *name != "x" || *port != 8080 {
+		t.Errorf("Unexpected values: %v %v %q %v", *verbose, *timeout, *name, *port)
+	}
+	if !reflect.DeepEqual(args, []string{"rest"}) {
+		t.Errorf("Unexpected args: %v", args)
+	}
+
+	expected := `Usage: old [OPTIONS]
+
+Options:
+  -name, --name STRING         user name
+  -port, --port INT            listen port (default: 8080)
+  -timeout, --timeout TIMEOUT  request timeout (default: 30s)
+  -v                           enable verbose output
+`
+	if usage := b.Usage(); usage != expected {
+		t.Errorf("Unexpected usage:\n%s\nexpected:\n%s", usage, expected)
+	}
+
+	_, err = b.Parse([]string{"--port", "x"})
+	if err == nil || !strings.Contains(err.Error(), "error setting value for --port") {
+		t.Errorf("Expected value error, got: %v", err)
+	}
+}
+
+func TestExportFlagSet(t *testing.T) {
+	var verbose int
+	var timeout time.Duration
+	var files []string
+	var name string
+	b := Count("-v", &verbose).
+		Duration("--timeout", &timeout).Default("30s").Desc("request timeout").
+		StringSlice("--file", &files).
+		String("-n,--name", &name).
+		Help("-h,--help", "")
+
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	err := b.ExportFlagSet(fs)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if timeout != 30*time.Second {
+		t.Errorf("Expected default applied on export, got %v", timeout)
+	}
+	if f := fs.Lookup("timeout"); f == nil || f.Usage != "request timeout" || f.DefValue != "30s" {
+		t.Errorf("Unexpected exported flag: %+v", f)
+	}
+	if fs.Lookup("h") != nil {
+		t.Errorf("Expected help flag to be skipped")
+	}
+
+	err = fs.Parse([]string{"-v", "-v", "-file", "a", "--file=b", "-name", "x", "rest"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if verbose != 2 || name != "x" || !reflect.DeepEqual(files, []string{"a", "b"}) {
+		t.Errorf("Unexpected values: %v %q %v", verbose, name, files)
+	}
+	if !reflect.DeepEqual(fs.Args(), []string{"rest"}) {
+		t.Errorf("Unexpected args: %v", fs.Args())
+	}
+}
+
+func TestExportFlagSet_Redefined(t *testing.T) {
+	var name, verbose string
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	fs.String("verbose", "", "")
+	err := String("-n,--name", &name).
+		String("--verbose", &verbose).
+		ExportFlagSet(fs)
+	if err == nil || err.Error() != "flag verbose already defined on new" {
+		t.Fatalf("Expected redefined error, got: %v", err)
+	}
+	if fs.Lookup("name") != nil {
+		t.Errorf("Expected no flag defined on error")
+	}
+}
+
+func TestExportFlagSet_ImportedFlags(t *testing.T) {
+	src := flag.NewFlagSet("src", flag.ContinueOnError)
+	level := src.String("level", "info", "log level")
+
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	err := FromFlagSet(src).ExportFlagSet(fs)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	err = fs.Parse([]string{"--level", "debug"})
+	if err != nil || *level != "debug" {
+		t.Errorf("Unexpected level: %q, err: %v", *level, err)
+	}
+}
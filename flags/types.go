@@ -0,0 +1,230 @@
+package flags
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Float creates a new builder and adds a float flag
+func Float(names string, target interface{}) *Builder {
+	return (&Builder{}).Float(names, target)
+}
+
+// IntSlice creates a new builder and adds an integer slice flag
+func IntSlice(names string, target interface{}) *Builder {
+	return (&Builder{}).IntSlice(names, target)
+}
+
+// StringMap creates a new builder and adds a key=value map flag
+func StringMap(names string, target interface{}) *Builder {
+	return (&Builder{}).StringMap(names, target)
+}
+
+// Time creates a new builder and adds a time flag
+func Time(names string, target interface{}, layouts ...string) *Builder {
+	return (&Builder{}).Time(names, target, layouts...)
+}
+
+// Enum creates a new builder and adds a string flag restricted to choices
+func Enum(names string, target interface{}, choices ...string) *Builder {
+	return (&Builder{}).Enum(names, target, choices...)
+}
+
+// Float adds a float flag to the builder, target can be
+// *float64, *float32 or pointers to them
+func (b *Builder) Float(names string, target interface{}) *Builder {
+	b.addFlag(parseNames(names), FlagTypeFloat, target)
+	return b
+}
+
+// IntSlice adds an integer slice flag to the builder,
+// each occurrence appends to the slice
+func (b *Builder) IntSlice(names string, target interface{}) *Builder {
+	b.addFlag(parseNames(names), FlagTypeIntSlice, target)
+	return b
+}
+
+// StringMap adds a map[string]string flag to the builder,
+// each occurrence like --label k=v adds one entry
+func (b *Builder) StringMap(names string, target interface{}) *Builder {
+	b.addFlag(parseNames(names), FlagTypeStringMap, target)
+	return b
+}
+
+// Time adds a time.Time flag to the builder. The value is parsed
+// with the first matching layout, defaulting to time.RFC3339,
+// 2006-01-02T15:04:05 and 2006-01-02.
+func (b *Builder) Time(names string, target interface{}, layouts ...string) *Builder {
+	spec := b.addFlag(parseNames(names), FlagTypeTime, target)
+	if v, ok := spec.Value.(timeValue); ok && len(layouts) > 0 {
+		v.layouts = layouts
+		spec.Value = v
+	}
+	return b
+}
+
+// Enum adds a string flag whose value must be one of choices,
+// the choices are listed in help and in errors
+func (b *Builder) Enum(names string, target interface{}, choices ...string) *Builder {
+	spec := b.addFlag(parseNames(names), FlagTypeEnum, target)
+	if v, ok := spec.Value.(enumValue); ok {
+		v.choices = choices
+		spec.Value = v
+	}
+	return b
+}
+
+// Split makes the last added slice or map flag split each value
+// by sep, so --files a,b is the same as --files a --files b
+func (b *Builder) Split(sep string) *Builder {
+	b.lastSpec("Split").Separator = sep
+	return b
+}
+
+// choicesValue is implemented by values restricted to a fixed set,
+// the choices are listed in help and offered by completion
+type choicesValue interface {
+	Choices() []string
+}
+
+type floatValue struct{ target }
+
+func (v floatValue) Set(s string) error {
+	elem := v.elem()
+	f, err := strconv.ParseFloat(s, elem.Type().Bits())
+	if err != nil {
+		return err
+	}
+	elem.SetFloat(f)
+	return nil
+}
+
+func (v floatValue) String() string {
+	if elem, ok := v.get(); ok {
+		return strconv.FormatFloat(elem.Float(), 'g', -1, elem.Type().Bits())
+	}
+	return ""
+}
+
+func (v floatValue) Type() string { return "float" }
+
+type intSliceValue struct{ target }
+
+// Set appends s, so the flag can be repeated
+func (v intSliceValue) Set(s string) error {
+	elem := v.elem()
+	n := reflect.New(elem.Type().Elem()).Elem()
+	err := setInt(n, s)
+	if err != nil {
+		return err
+	}
+	elem.Set(reflect.Append(elem, n))
+	return nil
+}
+
+func (v intSliceValue) String() string {
+	elem, ok := v.get()
+	if !ok {
+		return ""
+	}
+	strs := make([]string, 0, elem.Len())
+	for i := 0; i < elem.Len(); i++ {
+		strs = append(strs, fmt.Sprint(elem.Index(i).Interface()))
+	}
+	return strings.Join(strs, ",")
+}
+
+func (v intSliceValue) Type() string { return "ints" }
+
+type stringMapValue struct{ target }
+
+// Set adds the key=value entry s
+func (v stringMapValue) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("expect key=value, actual: %s", s)
+	}
+	elem := v.elem()
+	if elem.IsNil() {
+		elem.Set(reflect.MakeMap(elem.Type()))
+	}
+	elem.SetMapIndex(reflect.ValueOf(key).Convert(elem.Type().Key()), reflect.ValueOf(value).Convert(elem.Type().Elem()))
+	return nil
+}
+
+func (v stringMapValue) String() string {
+	elem, ok := v.get()
+	if !ok {
+		return ""
+	}
+	keys := make([]string, 0, elem.Len())
+	for _, key := range elem.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+elem.MapIndex(reflect.ValueOf(key).Convert(elem.Type().Key())).String())
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v stringMapValue) Type() string { return "key=value" }
+
+var timeType = reflect.TypeOf(time.Time{})
+
+var defaultTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+type timeValue struct {
+	target
+	layouts []string
+}
+
+func (v timeValue) Set(s string) error {
+	for _, layout := range v.layouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			v.elem().Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid time %q, expect layout: %s", s, strings.Join(v.layouts, " or "))
+}
+
+func (v timeValue) String() string {
+	if elem, ok := v.get(); ok {
+		return elem.Interface().(time.Time).Format(v.layouts[0])
+	}
+	return ""
+}
+
+func (v timeValue) Type() string { return "time" }
+
+type enumValue struct {
+	target
+	choices []string
+}
+
+func (v enumValue) Set(s string) error {
+	for _, choice := range v.choices {
+		if s == choice {
+			v.elem().SetString(s)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value %q, expect one of: %s", s, strings.Join(v.choices, ", "))
+}
+
+func (v enumValue) String() string {
+	if elem, ok := v.get(); ok {
+		return elem.String()
+	}
+	return ""
+}
+
+func (v enumValue) Type() string      { return "string" }
+func (v enumValue) Choices() []string { return v.choices }
@@ -0,0 +1,171 @@
+package flags
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFloat(t *testing.T) {
+	var ratio float64
+	var scale *float32
+	_, err := Float("--ratio", &ratio).Float("--scale", &scale).Parse([]string{"--ratio", "0.75", "--scale=1.5"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if ratio != 0.75 {
+		t.Errorf("Expected ratio=0.75, got %v", ratio)
+	}
+	if scale == nil || *scale != 1.5 {
+		t.Errorf("Expected scale=1.5, got %v", scale)
+	}
+}
+
+func TestInt_Range(t *testing.T) {
+	var small int8
+	var port uint16
+	var big uint64
+
+	_, err := Int("--small", &small).Int("--port", &port).Int("--big", &big).
+		Parse([]string{"--small", "-128", "--port", "65535", "--big", "18446744073709551615"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if small != -128 || port != 65535 || big != 18446744073709551615 {
+		t.Errorf("Expected boundary values, got small=%d port=%d big=%d", small, port, big)
+	}
+
+	tests := []struct {
+		args   []string
+		expect string
+	}{
+		{[]string{"--small", "128"}, "128 out of range for int8"},
+		{[]string{"--port", "65536"}, "65536 out of range for uint16"},
+		{[]string{"--port", "-1"}, "invalid syntax"},
+		{[]string{"--big", "x"}, "invalid syntax"},
+	}
+	for _, tt := range tests {
+		_, err := Int("--small", &small).Int("--port", &port).Int("--big", &big).Parse(tt.args)
+		if err == nil || !strings.Contains(err.Error(), tt.expect) {
+			t.Errorf("Parse(%v): expected error containing %q, got: %v", tt.args, tt.expect, err)
+		}
+	}
+}
+
+func TestIntSlice(t *testing.T) {
+	var ids []int
+	var codes []uint8
+	_, err := IntSlice("--id", &ids).IntSlice("--code", &codes).Split(",").
+		Parse([]string{"--id", "1", "--id=2", "--code", "3,4"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Errorf("Expected ids=[1 2], got %v", ids)
+	}
+	if len(codes) != 2 || codes[0] != 3 || codes[1] != 4 {
+		t.Errorf("Expected codes=[3 4], got %v", codes)
+	}
+
+	_, err = IntSlice("--code", &codes).Parse([]string{"--code", "256"})
+	if err == nil || !strings.Contains(err.Error(), "256 out of range for uint8") {
+		t.Errorf("Expected range error, got: %v", err)
+	}
+}
+
+func TestStringSlice_Split(t *testing.T) {
+	var files []string
+	_, err := StringSlice("--files", &files).Split(",").Parse([]string{"--files", "a,b", "--files", "c"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if strings.Join(files, " ") != "a b c" {
+		t.Errorf("Expected files=[a b c], got %v", files)
+	}
+}
+
+func TestStringMap(t *testing.T) {
+	var labels map[string]string
+	_, err := StringMap("--label", &labels).Parse([]string{"--label", "app=web", "--label=env=prod"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(labels) != 2 || labels["app"] != "web" || labels["env"] != "prod" {
+		t.Errorf("Expected labels app=web env=prod, got %v", labels)
+	}
+
+	_, err = StringMap("--label", &labels).Parse([]string{"--label", "app"})
+	if err == nil || !strings.Contains(err.Error(), "expect key=value, actual: app") {
+		t.Errorf("Expected key=value error, got: %v", err)
+	}
+}
+
+func TestTime(t *testing.T) {
+	var since time.Time
+	var until *time.Time
+	_, err := Time("--since", &since).Time("--until", &until, "2006/01/02").
+		Parse([]string{"--since", "2024-03-01", "--until", "2024/03/02"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !since.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected since=2024-03-01, got %v", since)
+	}
+	if until == nil || !until.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected until=2024/03/02, got %v", until)
+	}
+
+	_, err = Time("--since", &since).Parse([]string{"--since", "yesterday"})
+	if err == nil || !strings.Contains(err.Error(), `invalid time "yesterday"`) {
+		t.Errorf("Expected time error, got: %v", err)
+	}
+}
+
+func TestEnum(t *testing.T) {
+	var level string
+	b := Enum("--level", &level, "debug", "info", "error").Desc("log level")
+	_, err := b.Parse([]string{"--level", "info"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if level != "info" {
+		t.Errorf("Expected level=info, got %q", level)
+	}
+
+	_, err = b.Parse([]string{"--level", "trace"})
+	if err == nil || !strings.Contains(err.Error(), `invalid value "trace", expect one of: debug, info, error`) {
+		t.Errorf("Expected enum error, got: %v", err)
+	}
+
+	usage := b.Usage()
+	if !strings.Contains(usage, "--level STRING  log level (one of: debug, info, error)") {
+		t.Errorf("Expected choices in usage, got:\n%s", usage)
+	}
+	if got := formatCompletions(b.complete([]string{"--level", "d"})); got != "debug :nofiles" {
+		t.Errorf("Expected choices in completion, got %q", got)
+	}
+}
+
+func TestBindStruct_RicherTypes(t *testing.T) {
+	var opts struct {
+		Ratio  float64           `flag:"--ratio"`
+		IDs    []int             `flag:"--id" sep:","`
+		Labels map[string]string `flag:"--label"`
+		Since  time.Time         `flag:"--since"`
+		Level  string            `flag:"--level" enum:"debug,info"`
+	}
+	_, err := BindStruct(&opts).Parse([]string{
+		"--ratio", "0.5", "--id", "1,2", "--label", "a=b", "--since", "2024-01-02", "--level", "debug",
+	})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if opts.Ratio != 0.5 || len(opts.IDs) != 2 || opts.Labels["a"] != "b" || opts.Since.Day() != 2 || opts.Level != "debug" {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+
+	_, err = BindStruct(&opts).Parse([]string{"--level", "trace"})
+	if err == nil || !strings.Contains(err.Error(), "expect one of: debug, info") {
+		t.Errorf("Expected enum error, got: %v", err)
+	}
+}
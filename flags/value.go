@@ -2,9 +2,54 @@ package flags
 
 import (
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// Value is the interface to the dynamic value of a flag.
+// It matches flag.Value from the standard library with an
+// additional Type used as the default placeholder in help.
+//
+// A Value with an IsBoolFlag() bool method returning true
+// does not consume the next argument as its value.
+type Value interface {
+	String() string
+	Set(string) error
+	Type() string
+}
+
+type boolFlag interface {
+	IsBoolFlag() bool
+}
+
+// Var creates a new builder and adds a flag with a custom value
+func Var(names string, value Value) *Builder {
+	return (&Builder{}).Var(names, value)
+}
+
+// Var adds a flag whose values are parsed by value
+func (b *Builder) Var(names string, value Value) *Builder {
+	b.flagSpecs = append(b.flagSpecs, FlagSpec{
+		Names: parseNames(names),
+		Type:  FlagTypeValue,
+		Value: value,
+	})
+	return b
+}
+
+// isBool reports whether the flag is a switch without value
+func (spec *FlagSpec) isBool() bool {
+	if spec.Type == FlagTypeBool {
+		return true
+	}
+	if bf, ok := spec.Value.(boolFlag); ok {
+		return bf.IsBoolFlag()
+	}
+	return false
+}
+
 func OnlyArg(args []string) (string, error) {
 	if len(args) == 0 {
 		return "", fmt.Errorf("requires an argument")
@@ -14,3 +59,283 @@ func OnlyArg(args []string) (string, error) {
 	}
 	return args[0], nil
 }
+
+// newValue creates the built-in Value of flagType for target
+func newValue(flagType FlagType, target interface{}) (Value, error) {
+	switch flagType {
+	case FlagTypeBool:
+		t, err := newTarget(target, "*bool or **bool", isKind(reflect.Bool))
+		return boolValue{t}, err
+	case FlagTypeString:
+		t, err := newTarget(target, "*string or **string", isKind(reflect.String))
+		return stringValue{t}, err
+	case FlagTypeDuration:
+		t, err := newTarget(target, "*time.Duration or **time.Duration", func(t reflect.Type) bool {
+			return t == durationType
+		})
+		return durationValue{t}, err
+	case FlagTypeInt:
+		t, err := newTarget(target, "*int or **int", isInt)
+		return intValue{t}, err
+	case FlagTypeCount:
+		t, err := newTarget(target, "*int or **int", isKind(
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		))
+		return countValue{t}, err
+	case FlagTypeStringSlice:
+		t, err := newTarget(target, "*[]string or **[]string", func(t reflect.Type) bool {
+			return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String
+		})
+		return stringSliceValue{t}, err
+	case FlagTypeFloat:
+		t, err := newTarget(target, "*float64 or **float64", isKind(reflect.Float32, reflect.Float64))
+		return floatValue{t}, err
+	case FlagTypeIntSlice:
+		t, err := newTarget(target, "*[]int or **[]int", func(t reflect.Type) bool {
+			return t.Kind() == reflect.Slice && isInt(t.Elem())
+		})
+		return intSliceValue{t}, err
+	case FlagTypeStringMap:
+		t, err := newTarget(target, "*map[string]string or **map[string]string", func(t reflect.Type) bool {
+			return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.String
+		})
+		return stringMapValue{t}, err
+	case FlagTypeTime:
+		t, err := newTarget(target, "*time.Time or **time.Time", func(t reflect.Type) bool {
+			return t == timeType
+		})
+		return timeValue{target: t, layouts: defaultTimeLayouts}, err
+	case FlagTypeEnum:
+		t, err := newTarget(target, "*string or **string", isKind(reflect.String))
+		return enumValue{target: t}, err
+	default:
+		return nil, fmt.Errorf("unsupported flag type")
+	}
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+var isInt = isKind(
+	reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+	reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+)
+
+func isKind(kinds ...reflect.Kind) func(t reflect.Type) bool {
+	return func(t reflect.Type) bool {
+		for _, kind := range kinds {
+			if t.Kind() == kind {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// target is a *T or **T flag target. For **T, the inner
+// pointer stays nil until a value is set.
+type target struct {
+	ptr reflect.Value
+}
+
+func newTarget(v interface{}, expect string, match func(t reflect.Type) bool) (target, error) {
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		return target{}, fmt.Errorf("target must be a pointer")
+	}
+	t := ptr.Type().Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if !match(t) {
+		return target{}, fmt.Errorf("target must be %s, actual: %v", expect, ptr.Type())
+	}
+	return target{ptr: ptr}, nil
+}
+
+// elem returns the settable T, allocating the inner pointer of **T
+func (t target) elem() reflect.Value {
+	elem := t.ptr.Elem()
+	if elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			elem.Set(reflect.New(elem.Type().Elem()))
+		}
+		elem = elem.Elem()
+	}
+	return elem
+}
+
+// get returns the current T, false if the inner pointer of **T is nil
+func (t target) get() (reflect.Value, bool) {
+	if !t.ptr.IsValid() {
+		return reflect.Value{}, false
+	}
+	elem := t.ptr.Elem()
+	if elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			return reflect.Value{}, false
+		}
+		elem = elem.Elem()
+	}
+	return elem, true
+}
+
+type boolValue struct{ target }
+
+// Set accepts the spellings of strconv.ParseBool,
+// an empty value means true
+func (v boolValue) Set(s string) error {
+	b := true
+	if s != "" {
+		var err error
+		b, err = strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q, expect true or false", s)
+		}
+	}
+	v.elem().SetBool(b)
+	return nil
+}
+
+func (v boolValue) String() string {
+	if elem, ok := v.get(); ok {
+		return strconv.FormatBool(elem.Bool())
+	}
+	return ""
+}
+
+func (v boolValue) Type() string     { return "bool" }
+func (v boolValue) IsBoolFlag() bool { return true }
+
+type stringValue struct{ target }
+
+func (v stringValue) Set(s string) error {
+	v.elem().SetString(s)
+	return nil
+}
+
+func (v stringValue) String() string {
+	if elem, ok := v.get(); ok {
+		return elem.String()
+	}
+	return ""
+}
+
+func (v stringValue) Type() string { return "string" }
+
+type durationValue struct{ target }
+
+func (v durationValue) Set(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	v.elem().SetInt(int64(d))
+	return nil
+}
+
+func (v durationValue) String() string {
+	if elem, ok := v.get(); ok {
+		return time.Duration(elem.Int()).String()
+	}
+	return ""
+}
+
+func (v durationValue) Type() string { return "duration" }
+
+type intValue struct{ target }
+
+func (v intValue) Set(s string) error {
+	return setInt(v.elem(), s)
+}
+
+func (v intValue) String() string {
+	elem, ok := v.get()
+	if !ok {
+		return ""
+	}
+	switch elem.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(elem.Uint(), 10)
+	default:
+		return strconv.FormatInt(elem.Int(), 10)
+	}
+}
+
+func (v intValue) Type() string { return "int" }
+
+// setInt parses s into the integer elem, rejecting values
+// out of the range of elem's type
+func setInt(elem reflect.Value, s string) error {
+	switch elem.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, elem.Type().Bits())
+		if err != nil {
+			return intError(s, elem.Type(), err)
+		}
+		elem.SetUint(n)
+	default:
+		n, err := strconv.ParseInt(s, 10, elem.Type().Bits())
+		if err != nil {
+			return intError(s, elem.Type(), err)
+		}
+		elem.SetInt(n)
+	}
+	return nil
+}
+
+func intError(s string, t reflect.Type, err error) error {
+	if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+		return fmt.Errorf("%s out of range for %v", s, t)
+	}
+	return err
+}
+
+type countValue struct{ target }
+
+// Set increments the count, or sets it to s if not empty
+func (v countValue) Set(s string) error {
+	elem := v.elem()
+	if s == "" {
+		elem.SetInt(elem.Int() + 1)
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	elem.SetInt(int64(n))
+	return nil
+}
+
+func (v countValue) String() string {
+	if elem, ok := v.get(); ok {
+		return strconv.FormatInt(elem.Int(), 10)
+	}
+	return ""
+}
+
+func (v countValue) Type() string     { return "count" }
+func (v countValue) IsBoolFlag() bool { return true }
+
+type stringSliceValue struct{ target }
+
+// Set appends s, so the flag can be repeated
+func (v stringSliceValue) Set(s string) error {
+	elem := v.elem()
+	elem.Set(reflect.Append(elem, reflect.ValueOf(s).Convert(elem.Type().Elem())))
+	return nil
+}
+
+func (v stringSliceValue) String() string {
+	elem, ok := v.get()
+	if !ok {
+		return ""
+	}
+	strs := make([]string, 0, elem.Len())
+	for i := 0; i < elem.Len(); i++ {
+		strs = append(strs, elem.Index(i).String())
+	}
+	return strings.Join(strs, ",")
+}
+
+func (v stringSliceValue) Type() string { return "strings" }
@@ -0,0 +1,108 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testLevel int
+
+func (l *testLevel) Set(s string) error {
+	switch s {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	case "error":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level: %s", s)
+	}
+	return nil
+}
+
+func (l *testLevel) String() string {
+	return [...]string{"debug", "info", "error"}[*l]
+}
+
+func (l *testLevel) Type() string { return "level" }
+
+type testSwitch struct{ on bool }
+
+func (s *testSwitch) Set(v string) error { s.on = v != "off"; return nil }
+func (s *testSwitch) String() string     { return fmt.Sprint(s.on) }
+func (s *testSwitch) Type() string       { return "switch" }
+func (s *testSwitch) IsBoolFlag() bool   { return true }
+
+func TestVar(t *testing.T) {
+	var level testLevel
+	var sw testSwitch
+	remainArgs, err := Var("--level", &level).
+		Var("--switch", &sw).
+		Parse([]string{"--level", "error", "--switch", "rest"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if level != 2 {
+		t.Errorf("Expected level=error, got %v", level.String())
+	}
+	if !sw.on {
+		t.Errorf("Expected bool value to be set without consuming an argument")
+	}
+	if len(remainArgs) != 1 || remainArgs[0] != "rest" {
+		t.Errorf("Expected remainArgs=['rest'], got %v", remainArgs)
+	}
+}
+
+func TestVar_Error(t *testing.T) {
+	var level testLevel
+	_, err := Var("--level", &level).Parse([]string{"--level", "trace"})
+	if err == nil || !strings.Contains(err.Error(), "error setting value for --level: unknown level: trace") {
+		t.Errorf("Expected level error, got: %v", err)
+	}
+}
+
+func TestVar_Usage(t *testing.T) {
+	var level testLevel
+	usage := Var("--level", &level).Desc("log level").Default("info").Usage()
+	if !strings.Contains(usage, "--level LEVEL  log level (default: info)") {
+		t.Errorf("Expected placeholder from Type(), got:\n%s", usage)
+	}
+}
+
+func TestBuiltinValue_String(t *testing.T) {
+	var name *string
+	v, err := newValue(FlagTypeString, &name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.String() != "" || name != nil {
+		t.Errorf("Expected unset **string to stay nil, got %v", name)
+	}
+	if err := v.Set("x"); err != nil {
+		t.Fatal(err)
+	}
+	if v.String() != "x" || name == nil || *name != "x" {
+		t.Errorf("Expected name='x', got %v", name)
+	}
+
+	var files []string
+	v, err = newValue(FlagTypeStringSlice, &files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.Set("a")
+	v.Set("b")
+	if v.String() != "a,b" {
+		t.Errorf("Expected 'a,b', got '%s'", v.String())
+	}
+}
+
+func TestBuiltinValue_TargetMismatch(t *testing.T) {
+	var port string
+	_, err := Int("--port", &port).Parse(nil)
+	if err == nil || !strings.Contains(err.Error(), "--port: target must be *int or **int, actual: *string") {
+		t.Errorf("Expected target type error, got: %v", err)
+	}
+}
@@ -0,0 +1,342 @@
+package basic
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+func (c *Conv) declStmt(f *ast.DeclStmt) string {
+	g := f.Decl.(*ast.GenDecl)
+	var codes []string
+	for _, spec := range g.Specs {
+		switch spec := spec.(type) {
+		case *ast.ValueSpec:
+			codes = append(codes, c.valueSpec(g.Tok, spec, false))
+		case *ast.TypeSpec:
+			codes = append(codes, c.typeSpec(spec, true))
+		default:
+			c.unsupported(spec, "declaration %T", spec)
+		}
+	}
+	return joinCode(codes)
+}
+
+// valueSpec translates var and const declarations, package level
+// names are declared beforehand as they are visible in all files
+func (c *Conv) valueSpec(tok token.Token, s *ast.ValueSpec, pkgLevel bool) string {
+	declare := c.declare
+	var modifier string
+	if pkgLevel {
+		declare = c.name
+	}
+
+	var codes []string
+	if len(s.Names) > 1 && len(s.Values) == 1 {
+		value := c.tupleExpr(s.Values[0])
+		objs := make([]types.Object, len(s.Names))
+		names := make([]string, len(s.Names))
+		for i, id := range s.Names {
+			if id.Name != "_" {
+				objs[i] = c.typesInfo.Defs[id]
+				names[i] = declare(objs[i])
+			}
+		}
+		kw := c.declKeyword(objs...)
+		if pkgLevel {
+			kw = "let"
+			if isExported(s.Names[0].Name) {
+				modifier = "export "
+			}
+		}
+		return fmt.Sprintf("%s%s [%s] = %s;", modifier, kw, strings.Join(names, ", "), value)
+	}
+	for i, id := range s.Names {
+		if id.Name == "_" {
+			if i < len(s.Values) {
+				codes = append(codes, c.discard(s.Values[i]))
+			}
+			continue
+		}
+		obj := c.typesInfo.Defs[id]
+		if pkgLevel && isExported(id.Name) {
+			modifier = "export "
+		} else {
+			modifier = ""
+		}
+		if tok == token.CONST {
+			value := c.constValue(obj.(*types.Const).Val())
+			codes = append(codes, fmt.Sprintf("%sconst %s = %s;", modifier, declare(obj), value))
+			continue
+		}
+		var value string
+		if i < len(s.Values) {
+			value = c.valueExpr(s.Values[i])
+		} else {
+			value = c.zeroValue(obj.Type())
+		}
+		var annot string
+		if s.Type != nil {
+			annot = c.annot(obj.Type())
+		}
+		kw := c.declKeyword(obj)
+		if pkgLevel {
+			kw = "let"
+		}
+		codes = append(codes, fmt.Sprintf("%s%s %s%s = %s;", modifier, kw, declare(obj), annot, value))
+	}
+	return strings.Join(codes, "\n")
+}
+
+func (c *Conv) assignStmt(f *ast.AssignStmt) string {
+	switch f.Tok {
+	case token.DEFINE:
+		return c.defineStmt(f)
+	case token.ASSIGN:
+		return c.assign(f.Lhs, f.Rhs)
+	default:
+		return c.opAssign(f)
+	}
+}
+
+// defineStmt translates short variable declarations, which may
+// redeclare some of the variables
+func (c *Conv) defineStmt(f *ast.AssignStmt) string {
+	// the values are translated first, the new
+	// names are not in scope on the right side
+	tuple := len(f.Lhs) > 1 && len(f.Rhs) == 1
+	var values []string
+	if tuple {
+		values = []string{c.tupleExpr(f.Rhs[0])}
+	} else {
+		for _, rhs := range f.Rhs {
+			values = append(values, c.valueExpr(rhs))
+		}
+	}
+
+	objs := make([]types.Object, len(f.Lhs))
+	allNew := true
+	for i, lhs := range f.Lhs {
+		if isBlank(lhs) {
+			continue
+		}
+		objs[i] = c.typesInfo.Defs[lhs.(*ast.Ident)]
+		if objs[i] == nil {
+			allNew = false
+		}
+	}
+
+	var codes []string
+	switch {
+	case allNew && tuple:
+		names := make([]string, len(objs))
+		for i, obj := range objs {
+			if obj != nil {
+				names[i] = c.declare(obj)
+			}
+		}
+		return fmt.Sprintf("%s [%s] = %s;", c.declKeyword(objs...), strings.Join(names, ", "), values[0])
+	case allNew:
+		for i, obj := range objs {
+			if obj == nil {
+				codes = append(codes, c.discard(f.Rhs[i]))
+				continue
+			}
+			codes = append(codes, fmt.Sprintf("%s %s = %s;", c.declKeyword(obj), c.declare(obj), values[i]))
+		}
+	default:
+		for _, obj := range objs {
+			if obj != nil {
+				codes = append(codes, fmt.Sprintf("let %s%s;", c.declare(obj), c.annot(obj.Type())))
+			}
+		}
+		value := values[0]
+		if !tuple {
+			value = "[" + strings.Join(values, ", ") + "]"
+		}
+		codes = append(codes, c.destructure(f.Lhs, value))
+	}
+	return joinCode(codes)
+}
+
+func (c *Conv) assign(lhs []ast.Expr, rhs []ast.Expr) string {
+	if len(lhs) == 1 {
+		if isBlank(lhs[0]) {
+			return c.discard(rhs[0])
+		}
+		return c.store(lhs[0], c.valueExpr(rhs[0]))
+	}
+	if len(rhs) == 1 {
+		return c.destructure(lhs, c.tupleExpr(rhs[0]))
+	}
+	values := make([]string, 0, len(rhs))
+	for _, e := range rhs {
+		values = append(values, c.valueExpr(e))
+	}
+	return c.destructure(lhs, "["+strings.Join(values, ", ")+"]")
+}
+
+// destructure assigns the elements of the array value to lhs,
+// blank identifiers are left as holes
+func (c *Conv) destructure(lhs []ast.Expr, value string) string {
+	targets := make([]string, len(lhs))
+	hasIndex := false
+	allBlank := true
+	for i, e := range lhs {
+		if isBlank(e) {
+			continue
+		}
+		allBlank = false
+		if _, _, ok := c.indexTarget(e); ok {
+			hasIndex = true
+		}
+		if _, ok := unparen(e).(*ast.StarExpr); ok {
+			hasIndex = true
+		}
+		targets[i] = c.expr(e)
+	}
+	if allBlank {
+		return value + ";"
+	}
+	if !hasIndex {
+		return fmt.Sprintf("[%s] = %s;", strings.Join(targets, ", "), value)
+	}
+
+	// map and slice entries cannot be destructuring targets
+	tmps := make([]string, len(lhs))
+	var stores []string
+	for i, e := range lhs {
+		if !isBlank(e) {
+			tmps[i] = c.fresh(fmt.Sprintf("_v%d", i))
+			stores = append(stores, c.store(e, tmps[i]))
+		}
+	}
+	return fmt.Sprintf("const [%s] = %s;\n%s", strings.Join(tmps, ", "), value, strings.Join(stores, "\n"))
+}
+
+// store assigns value to the addressable expression lhs
+func (c *Conv) store(lhs ast.Expr, value string) string {
+	if x, key, ok := c.indexTarget(lhs); ok {
+		return fmt.Sprintf("%s.set(%s, %s);", x, key, value)
+	}
+	if star, ok := unparen(lhs).(*ast.StarExpr); ok && isValue(c.typesInfo.TypeOf(lhs)) {
+		// the pointer is the object itself, which is overwritten
+		return fmt.Sprintf("Object.assign(%s, %s);", c.expr(star.X), value)
+	}
+	return fmt.Sprintf("%s = %s;", c.expr(lhs), value)
+}
+
+// discard evaluates e for its side effects, as in `_ = f()`
+func (c *Conv) discard(e ast.Expr) string {
+	switch unparen(e).(type) {
+	case *ast.Ident, *ast.BasicLit, *ast.SelectorExpr, *ast.FuncLit:
+		return ""
+	}
+	return c.expr(e) + ";"
+}
+
+// tupleExpr translates an expression assigned to multiple
+// variables into an array
+func (c *Conv) tupleExpr(e ast.Expr) string {
+	if index, ok := unparen(e).(*ast.IndexExpr); ok && isMap(c.typesInfo.TypeOf(index.X)) {
+		return fmt.Sprintf("%s.get2(%s)", c.expr(index.X), c.expr(index.Index))
+	}
+	if recv, ok := unparen(e).(*ast.UnaryExpr); ok && recv.Op == token.ARROW {
+		return c.expr(recv.X) + ".recv2()"
+	}
+	return c.expr(e)
+}
+
+// assignOps maps the assignment operators to their binary operators
+var assignOps = map[token.Token]token.Token{
+	token.ADD_ASSIGN:     token.ADD,
+	token.SUB_ASSIGN:     token.SUB,
+	token.MUL_ASSIGN:     token.MUL,
+	token.QUO_ASSIGN:     token.QUO,
+	token.REM_ASSIGN:     token.REM,
+	token.AND_ASSIGN:     token.AND,
+	token.OR_ASSIGN:      token.OR,
+	token.XOR_ASSIGN:     token.XOR,
+	token.SHL_ASSIGN:     token.SHL,
+	token.SHR_ASSIGN:     token.SHR,
+	token.AND_NOT_ASSIGN: token.AND_NOT,
+}
+
+func (c *Conv) opAssign(f *ast.AssignStmt) string {
+	op := assignOps[f.Tok]
+	lhs := f.Lhs[0]
+	y := c.expr(f.Rhs[0])
+	if x, key, ok := c.indexTarget(lhs); ok {
+		return fmt.Sprintf("%s.set(%s, %s);", x, key, c.binaryValue(c.expr(lhs), op, y, c.typesInfo.TypeOf(lhs)))
+	}
+	x := c.expr(lhs)
+	t := c.typesInfo.TypeOf(lhs)
+	if bits, _ := intBits(t); bits > 0 || op == token.QUO && isInteger(t) || op == token.AND_NOT {
+		return fmt.Sprintf("%s = %s;", x, c.binaryValue(x, op, y, t))
+	}
+	return fmt.Sprintf("%s %s %s;", x, f.Tok, y)
+}
+
+// binaryValue computes `x op y` where y is a whole expression
+func (c *Conv) binaryValue(x string, op token.Token, y string, t types.Type) string {
+	if strings.Contains(y, " ") {
+		y = "(" + y + ")"
+	}
+	return arith(x, op, y, t)
+}
+
+func (c *Conv) incDecStmt(f *ast.IncDecStmt) string {
+	op := token.ADD
+	if f.Tok == token.DEC {
+		op = token.SUB
+	}
+	t := c.typesInfo.TypeOf(f.X)
+	if x, key, ok := c.indexTarget(f.X); ok {
+		return fmt.Sprintf("%s.set(%s, %s);", x, key, c.binaryValue(c.expr(f.X), op, "1", t))
+	}
+	if bits, _ := intBits(t); bits > 0 {
+		x := c.expr(f.X)
+		return fmt.Sprintf("%s = %s;", x, c.binaryValue(x, op, "1", t))
+	}
+	return c.expr(f.X) + f.Tok.String() + ";"
+}
+
+func (c *Conv) returnStmt(f *ast.ReturnStmt) string {
+	res := c.sigs[len(c.sigs)-1].Results()
+	var values []string
+	if len(f.Results) == 0 {
+		// bare return of named results
+		for i := 0; i < res.Len(); i++ {
+			v := res.At(i)
+			if v.Name() == "_" {
+				values = append(values, c.zeroValue(v.Type()))
+			} else {
+				values = append(values, c.name(v))
+			}
+		}
+	} else {
+		for _, e := range f.Results {
+			values = append(values, c.resultExpr(e))
+		}
+	}
+	switch len(values) {
+	case 0:
+		return "return;"
+	case 1:
+		return "return " + values[0] + ";"
+	}
+	return "return [" + strings.Join(values, ", ") + "];"
+}
+
+// joinCode joins statements, skipping the empty ones
+func joinCode(codes []string) string {
+	list := codes[:0]
+	for _, code := range codes {
+		if code != "" {
+			list = append(list, code)
+		}
+	}
+	return strings.Join(list, "\n")
+}
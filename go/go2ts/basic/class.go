@@ -0,0 +1,275 @@
+package basic
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// findTypes collects the type declarations and the methods of
+// struct types, classes are emitted first as they are not hoisted
+func (c *Conv) findTypes(files []*ast.File) []*ast.TypeSpec {
+	var specs []*ast.TypeSpec
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range d.Specs {
+					specs = append(specs, spec.(*ast.TypeSpec))
+				}
+			case *ast.FuncDecl:
+				if tn := c.classOf(d); tn != nil {
+					c.methods[tn] = append(c.methods[tn], d)
+				}
+			}
+		}
+	}
+	return specs
+}
+
+// classOf returns the struct type a method is attached to
+func (c *Conv) classOf(f *ast.FuncDecl) *types.TypeName {
+	if f.Recv == nil {
+		return nil
+	}
+	return c.recvClass(c.typesInfo.Defs[f.Name].Type().(*types.Signature).Recv())
+}
+
+// recvClass returns the struct type of a receiver
+func (c *Conv) recvClass(recv *types.Var) *types.TypeName {
+	named, ok := deref(recv.Type()).(*types.Named)
+	if !ok || !isStruct(named) {
+		return nil
+	}
+	return named.Obj()
+}
+
+// typeSpec translates a struct type to a class, other named types
+// are replaced by their underlying types
+func (c *Conv) typeSpec(s *ast.TypeSpec, local bool) string {
+	obj := c.typesInfo.Defs[s.Name].(*types.TypeName)
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok || s.TypeParams != nil {
+		return ""
+	}
+	name := c.name(obj)
+	if local {
+		name = c.declare(obj)
+	}
+	var modifier string
+	if !local && isExported(name) {
+		modifier = "export "
+	}
+
+	members := make([]string, 0, st.NumFields()+2)
+	copies := make([]string, 0, st.NumFields())
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		members = append(members, fmt.Sprintf("%s%s = %s;", field.Name(), c.annot(field.Type()), c.zeroValue(field.Type())))
+		if clone := c.cloneValue("this."+field.Name(), field.Type()); clone != "this."+field.Name() {
+			copies = append(copies, field.Name()+": "+clone)
+		}
+	}
+	if fieldTypes := c.fieldTypes(st); fieldTypes != "" {
+		members = append(members, fmt.Sprintf("static readonly fieldTypes = { %s };", fieldTypes))
+	}
+	members = append(members, fmt.Sprintf("constructor(init?: Partial<%s>) {\nObject.assign(this, init);\n}", name))
+
+	// clone copies the struct value, which Go does on assignment
+	clone := "this"
+	if len(copies) > 0 {
+		clone = "{ ...this, " + strings.Join(copies, ", ") + " }"
+	}
+	members = append(members, fmt.Sprintf("clone(): %s {\nreturn new %s(%s);\n}", name, name, clone))
+	for _, m := range c.methods[obj] {
+		members = append(members, c.method(m))
+	}
+	return fmt.Sprintf("%sclass %s {\n%s\n}", modifier, name, strings.Join(members, "\n"))
+}
+
+// fieldTypes lists the types of the fields whose values do not tell
+// how fmt prints them: nil slices and maps are null and floats may
+// be integers
+func (c *Conv) fieldTypes(st *types.Struct) string {
+	var fields []string
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		switch u := field.Type().Underlying().(type) {
+		case *types.Basic:
+			if u.Info()&types.IsFloat == 0 {
+				continue
+			}
+		case *types.Slice, *types.Map:
+		default:
+			continue
+		}
+		fields = append(fields, fmt.Sprintf("%s: %s", field.Name(), strconv.Quote(goType(field.Type().Underlying()))))
+	}
+	return strings.Join(fields, ", ")
+}
+
+// cloneValue returns a copy of the value x of type t, which is
+// x itself unless t is a struct or an array
+func (c *Conv) cloneValue(x string, t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		if named, ok := t.(*types.Named); ok && named.Obj().Pkg() == c.typePkg {
+			return x + ".clone()"
+		}
+		return "{ ..." + x + " }"
+	case *types.Array:
+		if elem := c.cloneValue("v", u.Elem()); elem != "v" {
+			return fmt.Sprintf("%s.map((v) => %s)", x, elem)
+		}
+		return "[..." + x + "]"
+	}
+	return x
+}
+
+// method translates a method of a class, the receiver is this
+func (c *Conv) method(f *ast.FuncDecl) string {
+	obj := c.typesInfo.Defs[f.Name].(*types.Func)
+	sig := obj.Type().(*types.Signature)
+	params, body := c.funcBody(sig, f.Body)
+	return fmt.Sprintf("%s(%s)%s {\n%s\n}", f.Name.Name, params, c.resultAnnot(sig), body)
+}
+
+// receiver binds the receiver of a class method. A value receiver
+// is a copy in Go, so it is cloned if the method modifies it.
+func (c *Conv) receiver(recv *types.Var, body *ast.BlockStmt) string {
+	if recv.Name() == "" || recv.Name() == "_" {
+		return ""
+	}
+	_, isPtr := recv.Type().(*types.Pointer)
+	switch {
+	case !isPtr && c.modifies(body, recv):
+		return fmt.Sprintf("const %s = this.clone();", c.declare(recv))
+	case c.mutated[recv]:
+		return fmt.Sprintf("let %s = this;", c.declare(recv))
+	}
+	c.names[recv] = "this"
+	return ""
+}
+
+// modifies reports whether body changes the struct v, through
+// assignments to its fields or pointer methods
+func (c *Conv) modifies(body *ast.BlockStmt, v *types.Var) bool {
+	if body == nil {
+		return false
+	}
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				found = found || c.rootVar(lhs) == v
+			}
+		case *ast.IncDecStmt:
+			found = found || c.rootVar(n.X) == v
+		case *ast.UnaryExpr:
+			found = found || n.Op == token.AND && c.rootVar(n.X) == v
+		case *ast.SelectorExpr:
+			sel := c.typesInfo.Selections[n]
+			if sel != nil && sel.Kind() == types.MethodVal && c.rootVar(n.X) == v {
+				_, ptr := sel.Obj().Type().(*types.Signature).Recv().Type().(*types.Pointer)
+				found = found || ptr
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// rootVar returns the variable an addressable expression like
+// a.b[i].c is part of
+func (c *Conv) rootVar(e ast.Expr) types.Object {
+	for {
+		switch x := unparen(e).(type) {
+		case *ast.Ident:
+			return c.typesInfo.Uses[x]
+		case *ast.SelectorExpr:
+			if _, ok := c.typesInfo.TypeOf(x.X).Underlying().(*types.Pointer); ok {
+				return nil
+			}
+			e = x.X
+		case *ast.IndexExpr:
+			if _, ok := c.typesInfo.TypeOf(x.X).Underlying().(*types.Array); !ok {
+				return nil
+			}
+			e = x.X
+		default:
+			return nil
+		}
+	}
+}
+
+// methodFunc returns the name of a method of a non-struct type,
+// which is translated to a function taking the receiver first
+func (c *Conv) methodFunc(fn *types.Func) string {
+	recv := fn.Type().(*types.Signature).Recv()
+	named, _ := deref(recv.Type()).(*types.Named)
+	return c.name(named.Obj()) + "_" + fn.Name()
+}
+
+// selection translates x.sel through the embedded fields
+// that the selection goes through
+func (c *Conv) selection(x ast.Expr, s *types.Selection) string {
+	return c.selectionRecv(x, s) + "." + s.Obj().Name()
+}
+
+// selectionRecv returns the value holding the selected
+// field or method
+func (c *Conv) selectionRecv(x ast.Expr, s *types.Selection) string {
+	code := c.expr(x)
+	t := s.Recv()
+	path := s.Index()
+	for _, index := range path[:len(path)-1] {
+		field := deref(t).Underlying().(*types.Struct).Field(index)
+		code += "." + field.Name()
+		t = field.Type()
+	}
+	return code
+}
+
+// methodCall translates calls of methods, resolved through
+// the selection as they may be promoted from embedded fields
+func (c *Conv) methodCall(f *ast.CallExpr, sel *ast.SelectorExpr, s *types.Selection) string {
+	fn := s.Obj().(*types.Func)
+	args := c.callArgs(f)
+	if c.isMethodFunc(fn) {
+		recv := c.selectionRecv(sel.X, s)
+		return fmt.Sprintf("%s(%s)", c.methodFunc(fn), strings.Join(append([]string{recv}, args...), ", "))
+	}
+	return fmt.Sprintf("%s(%s)", c.selection(sel.X, s), strings.Join(args, ", "))
+}
+
+// isMethodFunc reports whether fn is a method of a named type
+// other than a struct or an interface
+func (c *Conv) isMethodFunc(fn *types.Func) bool {
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	named, ok := deref(recv.Type()).(*types.Named)
+	if !ok || named.Obj().Pkg() != c.typePkg {
+		return false
+	}
+	switch named.Underlying().(type) {
+	case *types.Struct, *types.Interface:
+		return false
+	}
+	return true
+}
+
+func deref(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
@@ -0,0 +1,421 @@
+package basic
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+func (c *Conv) ifStmt(f *ast.IfStmt) string {
+	if f.Init == nil {
+		return c.ifChain(f)
+	}
+	// variables of the init statement are visible in all branches
+	c.pushScope()
+	defer c.popScope()
+	return "{\n" + c.stmt(f.Init) + "\n" + c.ifChain(f) + "\n}"
+}
+
+func (c *Conv) ifChain(f *ast.IfStmt) string {
+	code := fmt.Sprintf("if (%s) %s", c.expr(f.Cond), c.blockStmt(f.Body))
+	switch e := f.Else.(type) {
+	case *ast.IfStmt:
+		code += " else " + c.ifStmt(e)
+	case *ast.BlockStmt:
+		code += " else " + c.blockStmt(e)
+	}
+	return code
+}
+
+// forStmt translates a for loop, label is the JS label of the loop
+// if any, which must stay on the loop when the init statement
+// needs a block
+func (c *Conv) forStmt(f *ast.ForStmt, label string) string {
+	c.pushScope()
+	defer c.popScope()
+	var cond string
+	if f.Cond != nil {
+		cond = c.expr(f.Cond)
+	}
+	if f.Init == nil && f.Post == nil {
+		if cond == "" {
+			cond = "true"
+		}
+		return labeled(label, fmt.Sprintf("while (%s) %s", cond, c.blockStmt(f.Body)))
+	}
+
+	var init, post string
+	if f.Init != nil {
+		init = c.forInit(f.Init)
+	}
+	if f.Post != nil {
+		post = strings.TrimSuffix(c.stmt(f.Post), ";")
+	}
+	if strings.Contains(init, "\n") {
+		// not a single statement, scope it in a block instead
+		return fmt.Sprintf("{\n%s\n%s\n}", init, labeled(label, fmt.Sprintf("for (; %s; %s) %s", cond, post, c.blockStmt(f.Body))))
+	}
+	return labeled(label, fmt.Sprintf("for (%s; %s; %s) %s", strings.TrimSuffix(init, ";"), cond, post, c.blockStmt(f.Body)))
+}
+
+// labeled puts label on the loop
+func labeled(label string, loop string) string {
+	if label == "" {
+		return loop
+	}
+	return label + ": " + loop
+}
+
+// forInit translates the init statement of a for loop, declaring
+// multiple variables in one statement as JS allows no more
+func (c *Conv) forInit(init ast.Stmt) string {
+	assign, ok := init.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != len(assign.Rhs) {
+		return c.stmt(init)
+	}
+	values := make([]string, 0, len(assign.Rhs))
+	for _, rhs := range assign.Rhs {
+		values = append(values, c.expr(rhs))
+	}
+	decls := make([]string, 0, len(assign.Lhs))
+	objs := make([]types.Object, 0, len(assign.Lhs))
+	for i, lhs := range assign.Lhs {
+		obj := c.typesInfo.Defs[lhs.(*ast.Ident)]
+		if obj == nil {
+			// blank or redeclared
+			return c.stmt(init)
+		}
+		objs = append(objs, obj)
+		decls = append(decls, c.declare(obj)+" = "+values[i])
+	}
+	return c.declKeyword(objs...) + " " + strings.Join(decls, ", ")
+}
+
+func (c *Conv) rangeStmt(f *ast.RangeStmt, label string) string {
+	c.pushScope()
+	defer c.popScope()
+
+	x := c.expr(f.X)
+	key, value := f.Key, f.Value
+	if key != nil && isBlank(key) {
+		key = nil
+	}
+	if value != nil && isBlank(value) {
+		value = nil
+	}
+
+	t := c.typesInfo.TypeOf(f.X).Underlying()
+	if p, ok := t.(*types.Pointer); ok {
+		// pointer to array
+		t = p.Elem().Underlying()
+	}
+	var iter string
+	pairs := false
+	switch t := t.(type) {
+	case *types.Basic:
+		if t.Info()&types.IsInteger != 0 {
+			return c.rangeInt(f, x, key, label)
+		}
+		iter, pairs = c.runtime()+".runes("+x+")", true
+	case *types.Map, *types.Slice:
+		// nil maps and slices are null
+		switch {
+		case key != nil && value != nil:
+			iter, pairs = c.runtime()+".entries("+x+")", true
+		case key != nil:
+			iter = c.runtime() + ".keys(" + x + ")"
+		default:
+			iter = c.runtime() + ".values(" + x + ")"
+		}
+	case *types.Chan:
+		iter = x
+	default:
+		switch {
+		case key != nil && value != nil:
+			iter, pairs = x+".entries()", true
+		case key != nil:
+			iter = x + ".keys()"
+		default:
+			iter = x
+		}
+	}
+
+	var target, head string
+	switch {
+	case key == nil && value == nil:
+		target = "const " + c.fresh("_")
+	case f.Tok == token.ASSIGN:
+		target = c.rangeTarget(key, value, pairs, c.expr)
+	default:
+		var objs []types.Object
+		target = c.rangeTarget(key, value, pairs, func(e ast.Expr) string {
+			obj := c.typesInfo.Defs[e.(*ast.Ident)]
+			objs = append(objs, obj)
+			return c.declare(obj)
+		})
+		kw := c.declKeyword(objs...)
+		if value != nil {
+			// the value is a copy of the element
+			obj := c.typesInfo.Defs[value.(*ast.Ident)]
+			if isValue(obj.Type()) && c.modifies(f.Body, obj.(*types.Var)) {
+				v := c.name(obj)
+				head = fmt.Sprintf("%s = %s;", v, c.cloneValue(v, obj.Type()))
+				kw = "let"
+			}
+		}
+		target = kw + " " + target
+	}
+	return labeled(label, fmt.Sprintf("for (%s of %s) %s", target, iter, c.blockWith(head, f.Body)))
+}
+
+// rangeTarget returns the loop variables, destructured if
+// the iteration yields index and value pairs
+func (c *Conv) rangeTarget(key ast.Expr, value ast.Expr, pairs bool, name func(e ast.Expr) string) string {
+	var k, v string
+	if key != nil {
+		k = name(key)
+	}
+	if value != nil {
+		v = name(value)
+	}
+	switch {
+	case !pairs:
+		return k + v
+	case value == nil:
+		return "[" + k + "]"
+	}
+	return "[" + k + ", " + v + "]"
+}
+
+// rangeInt translates ranging over an integer as a counting loop
+func (c *Conv) rangeInt(f *ast.RangeStmt, n string, key ast.Expr, label string) string {
+	if _, ok := f.X.(*ast.Ident); !ok && c.typesInfo.Types[f.X].Value == nil {
+		// the count is evaluated once
+		tmp := c.fresh("_n")
+		code := fmt.Sprintf("const %s = %s;\n%s", tmp, n, labeled(label, c.countLoop(f, tmp, key)))
+		return "{\n" + code + "\n}"
+	}
+	return labeled(label, c.countLoop(f, n, key))
+}
+
+// countLoop counts from 0 to n. The loop variable is a copy of
+// the counter when it is assigned or changed in the loop body,
+// which must not affect the iteration.
+func (c *Conv) countLoop(f *ast.RangeStmt, n string, key ast.Expr) string {
+	var obj types.Object
+	if key != nil && f.Tok == token.DEFINE {
+		obj = c.typesInfo.Defs[key.(*ast.Ident)]
+		if !c.mutated[obj] {
+			i := c.declare(obj)
+			return fmt.Sprintf("for (let %s = 0; %s < %s; %s++) %s", i, i, n, i, c.blockStmt(f.Body))
+		}
+	}
+	i := c.fresh("_i")
+	var head string
+	switch {
+	case obj != nil:
+		head = fmt.Sprintf("let %s = %s;", c.declare(obj), i)
+	case key != nil:
+		head = c.store(key, i)
+	}
+	return fmt.Sprintf("for (let %s = 0; %s < %s; %s++) %s", i, i, n, i, c.blockWith(head, f.Body))
+}
+
+func (c *Conv) switchStmt(f *ast.SwitchStmt) string {
+	if f.Init == nil && !c.valueTag(f) {
+		return c.switchBody(f)
+	}
+	c.pushScope()
+	defer c.popScope()
+	var init string
+	if f.Init != nil {
+		init = c.stmt(f.Init) + "\n"
+	}
+	return "{\n" + init + c.switchBody(f) + "\n}"
+}
+
+// valueTag reports whether the switch compares struct or array
+// values, which JS switches compare by reference
+func (c *Conv) valueTag(f *ast.SwitchStmt) bool {
+	return f.Tag != nil && isValue(c.typesInfo.TypeOf(f.Tag))
+}
+
+// switchBody translates a switch to a JS switch, a tagless
+// switch compares its cases to true. A struct or array tag is
+// evaluated once and compared with equal in the cases.
+func (c *Conv) switchBody(f *ast.SwitchStmt) string {
+	tag := "true"
+	var decl string
+	match := c.expr
+	if c.valueTag(f) {
+		tmp := c.fresh("_tag")
+		decl = fmt.Sprintf("const %s = %s;\n", tmp, c.expr(f.Tag))
+		match = func(e ast.Expr) string {
+			return fmt.Sprintf("%s.equal(%s, %s)", c.runtime(), tmp, c.expr(e))
+		}
+	} else if f.Tag != nil {
+		tag = c.expr(f.Tag)
+	}
+	clauses := make([]string, 0, len(f.Body.List))
+	for i, stmt := range f.Body.List {
+		clauses = append(clauses, c.caseClause(stmt.(*ast.CaseClause), match, i == len(f.Body.List)-1))
+	}
+	return fmt.Sprintf("%sswitch (%s) {\n%s\n}", decl, tag, strings.Join(clauses, "\n"))
+}
+
+// caseClause translates a case, match translates its expressions
+func (c *Conv) caseClause(f *ast.CaseClause, match func(e ast.Expr) string, last bool) string {
+	c.pushScope()
+	defer c.popScope()
+
+	var heads []string
+	for _, e := range f.List {
+		heads = append(heads, "case "+match(e)+":")
+	}
+	if f.List == nil {
+		heads = append(heads, "default:")
+	}
+	body := c.stmts(f.Body)
+	if !last && !endsFlow(f.Body) {
+		// Go cases do not fall through unless asked to
+		body = append(body, "break;")
+	}
+	if declares(f.Body) {
+		// JS cases share one block
+		return strings.Join(heads, "\n") + " {\n" + strings.Join(body, "\n") + "\n}"
+	}
+	return strings.Join(append(heads, body...), "\n")
+}
+
+// endsFlow reports whether the statements never complete normally
+// or fall through to the next case
+func endsFlow(list []ast.Stmt) bool {
+	if len(list) == 0 {
+		return false
+	}
+	switch s := list[len(list)-1].(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return s.Tok != token.GOTO
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		id, ok := call.Fun.(*ast.Ident)
+		return ok && id.Name == "panic"
+	}
+	return false
+}
+
+// declares reports whether the statements declare block scoped names
+func declares(list []ast.Stmt) bool {
+	for _, stmt := range list {
+		switch s := stmt.(type) {
+		case *ast.DeclStmt:
+			return true
+		case *ast.AssignStmt:
+			if s.Tok == token.DEFINE {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// selectStmt translates a select to a JS switch taking the first
+// ready case, where Go takes a random one. Without a default case
+// nothing else can make a case ready, so it deadlocks.
+func (c *Conv) selectStmt(f *ast.SelectStmt) string {
+	hasDefault := false
+	for _, stmt := range f.Body.List {
+		if stmt.(*ast.CommClause).Comm == nil {
+			hasDefault = true
+		}
+	}
+	clauses := make([]string, 0, len(f.Body.List)+1)
+	for i, stmt := range f.Body.List {
+		clauses = append(clauses, c.commClause(stmt.(*ast.CommClause), hasDefault && i == len(f.Body.List)-1))
+	}
+	if !hasDefault {
+		clauses = append(clauses, "default:\nthrow new Error(\"all goroutines are asleep - deadlock!\");")
+	}
+	return fmt.Sprintf("switch (true) {\n%s\n}", strings.Join(clauses, "\n"))
+}
+
+func (c *Conv) commClause(f *ast.CommClause, last bool) string {
+	c.pushScope()
+	defer c.popScope()
+
+	head := "default:"
+	var body []string
+	if f.Comm != nil {
+		ch, send := commChan(f.Comm)
+		ready := "canRecv"
+		if send {
+			ready = "canSend"
+		}
+		head = fmt.Sprintf("case %s.%s(%s):", c.runtime(), ready, c.expr(ch))
+		body = append(body, c.stmt(f.Comm))
+	}
+	body = append(body, c.stmts(f.Body)...)
+	if !last && !endsFlow(f.Body) {
+		body = append(body, "break;")
+	}
+	return head + " {\n" + strings.Join(body, "\n") + "\n}"
+}
+
+// commChan returns the channel of a select case and whether
+// the case sends to it
+func commChan(s ast.Stmt) (ast.Expr, bool) {
+	switch s := s.(type) {
+	case *ast.SendStmt:
+		return s.Chan, true
+	case *ast.ExprStmt:
+		return unparen(s.X).(*ast.UnaryExpr).X, false
+	case *ast.AssignStmt:
+		return unparen(s.Rhs[0]).(*ast.UnaryExpr).X, false
+	}
+	panic(fmt.Errorf("unexpected select case: %T", s))
+}
+
+func (c *Conv) labeledStmt(f *ast.LabeledStmt) string {
+	// loops may be translated with a block declaring temporaries
+	switch s := f.Stmt.(type) {
+	case *ast.ForStmt:
+		return c.forStmt(s, c.label(f.Label))
+	case *ast.RangeStmt:
+		return c.rangeStmt(s, c.label(f.Label))
+	}
+	return c.label(f.Label) + ": " + c.stmt(f.Stmt)
+}
+
+func (c *Conv) branchStmt(f *ast.BranchStmt) string {
+	switch f.Tok {
+	case token.BREAK, token.CONTINUE:
+		if f.Label != nil {
+			return f.Tok.String() + " " + c.label(f.Label) + ";"
+		}
+		return f.Tok.String() + ";"
+	case token.FALLTHROUGH:
+		// the next JS case follows without a break
+		return ""
+	}
+	return c.unsupported(f, "%s statement", f.Tok)
+}
+
+// label returns the JS name of a label, labels have their own
+// namespace so they only avoid the reserved words
+func (c *Conv) label(id *ast.Ident) string {
+	if tsReserved[id.Name] {
+		return id.Name + "_"
+	}
+	return id.Name
+}
+
+func (c *Conv) sendStmt(f *ast.SendStmt) string {
+	return fmt.Sprintf("%s.send(%s);", c.expr(f.Chan), c.valueExpr(f.Value))
+}
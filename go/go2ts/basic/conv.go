@@ -43,7 +43,10 @@ func LoadAndTranslate(args []string, opts *Options) ([]*Translate, error) {
 
 	results := make([]*Translate, 0, len(pkgs))
 	for _, pkg := range pkgs {
-		code := processPkg(fset, pkg)
+		code, err := processPkg(fset, pkg)
+		if err != nil {
+			return nil, fmt.Errorf("translating %s: %v", pkg.PkgPath, err)
+		}
 
 		results = append(results, &Translate{
 			PkgPath: pkg.PkgPath,
@@ -0,0 +1,649 @@
+package basic
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+func (c *Conv) expr(f ast.Expr) string {
+	// constant expressions are folded, except literals and named
+	// constants which keep their spelling
+	if tv, ok := c.typesInfo.Types[f]; ok && tv.Value != nil {
+		switch f.(type) {
+		case *ast.BasicLit, *ast.Ident:
+		default:
+			return c.constValue(tv.Value)
+		}
+	}
+	switch f := f.(type) {
+	case *ast.Ident:
+		return c.ident(f)
+	case *ast.BasicLit:
+		return c.basicLit(f)
+	case *ast.ParenExpr:
+		return "(" + c.expr(f.X) + ")"
+	case *ast.BinaryExpr:
+		return c.binaryExpr(f)
+	case *ast.UnaryExpr:
+		return c.unaryExpr(f)
+	case *ast.CallExpr:
+		return c.callExpr(f)
+	case *ast.SelectorExpr:
+		return c.selectorExpr(f)
+	case *ast.IndexExpr:
+		return c.indexExpr(f)
+	case *ast.SliceExpr:
+		return c.sliceExpr(f)
+	case *ast.StarExpr:
+		return c.pointer(f, c.typesInfo.TypeOf(f), f.X)
+	case *ast.FuncLit:
+		return c.funcLit(f)
+	case *ast.CompositeLit:
+		return c.compositeLit(f)
+	}
+	return c.unsupported(f, "expression %T", f)
+}
+
+// pointer translates taking the address of x or dereferencing x,
+// elem being the type pointed to. Pointers to structs and arrays
+// are the JS objects themselves, other values cannot be shared.
+func (c *Conv) pointer(f ast.Expr, elem types.Type, x ast.Expr) string {
+	if !isValue(elem) {
+		return c.unsupported(f, "pointer to %s", goType(elem))
+	}
+	return c.expr(x)
+}
+
+func (c *Conv) ident(f *ast.Ident) string {
+	switch obj := c.typesInfo.ObjectOf(f).(type) {
+	case nil:
+		return f.Name
+	case *types.Nil:
+		return "null"
+	case *types.Const:
+		if obj.Pkg() == nil {
+			return c.constValue(obj.Val())
+		}
+		return c.name(obj)
+	default:
+		return c.name(obj)
+	}
+}
+
+func (c *Conv) basicLit(f *ast.BasicLit) string {
+	switch f.Kind {
+	case token.INT:
+		// JS has no legacy octal literals
+		if len(f.Value) > 1 && f.Value[0] == '0' && f.Value[1] >= '0' && f.Value[1] <= '9' {
+			return c.constValue(c.typesInfo.Types[f].Value)
+		}
+		return f.Value
+	case token.FLOAT:
+		if strings.HasPrefix(f.Value, "0x") || strings.HasPrefix(f.Value, "0X") {
+			return c.constValue(c.typesInfo.Types[f].Value)
+		}
+		return f.Value
+	case token.STRING, token.CHAR:
+		// runes are numbers, strings are quoted for JS
+		return c.constValue(c.typesInfo.Types[f].Value)
+	default:
+		return c.unsupported(f, "%s literal", f.Kind)
+	}
+}
+
+// jsPrecedence ranks the binary operators as JS parses them,
+// which differs from Go for the bitwise and shift operators
+var jsPrecedence = map[token.Token]int{
+	token.LOR:  1,
+	token.LAND: 2,
+	token.OR:   3,
+	token.XOR:  4,
+	token.AND:  5, token.AND_NOT: 5,
+	token.EQL: 6, token.NEQ: 6,
+	token.LSS: 7, token.LEQ: 7, token.GTR: 7, token.GEQ: 7,
+	token.SHL: 8, token.SHR: 8,
+	token.ADD: 9, token.SUB: 9,
+	token.MUL: 10, token.QUO: 10, token.REM: 10,
+}
+
+func (c *Conv) binaryExpr(f *ast.BinaryExpr) string {
+	x := c.operand(f.X, f.Op, false)
+	y := c.operand(f.Y, f.Op, true)
+	switch f.Op {
+	case token.EQL, token.NEQ:
+		if isValue(c.typesInfo.TypeOf(f.X)) {
+			eq := fmt.Sprintf("%s.equal(%s, %s)", c.runtime(), c.expr(f.X), c.expr(f.Y))
+			if f.Op == token.NEQ {
+				return "!" + eq
+			}
+			return eq
+		}
+		if f.Op == token.NEQ {
+			return x + " !== " + y
+		}
+		return x + " === " + y
+	}
+	return arith(x, f.Op, y, c.typesInfo.TypeOf(f))
+}
+
+// arith computes `x op y` of type t from the operands x and y.
+// Integers up to 32 bits wrap around like in Go, the others have
+// 64 bits, which JS numbers cannot wrap.
+func arith(x string, op token.Token, y string, t types.Type) string {
+	bits, signed := intBits(t)
+	var code string
+	switch {
+	case op == token.QUO && isInteger(t):
+		code = fmt.Sprintf("Math.trunc(%s / %s)", x, y)
+	case op == token.MUL && bits == 32:
+		// the product may not be exact
+		code = fmt.Sprintf("Math.imul(%s, %s)", x, y)
+		if signed {
+			return code
+		}
+	case op == token.SHR && bits == 32 && !signed:
+		return x + " >>> " + y
+	case op == token.AND_NOT:
+		code = x + " & ~" + y
+	default:
+		code = x + " " + op.String() + " " + y
+	}
+	switch op {
+	case token.ADD, token.SUB, token.MUL, token.QUO, token.SHL:
+		return wrapInt(code, t)
+	case token.AND, token.OR, token.XOR, token.AND_NOT:
+		// JS bitwise operators give signed 32 bit integers
+		if bits == 32 && !signed {
+			return wrapInt(code, t)
+		}
+	}
+	return code
+}
+
+// intBits returns the size of the integer types up to 32 bits and
+// whether they are signed, it is 0 for other types
+func intBits(t types.Type) (int, bool) {
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return 0, false
+	}
+	switch b.Kind() {
+	case types.Int8:
+		return 8, true
+	case types.Int16:
+		return 16, true
+	case types.Int32:
+		return 32, true
+	case types.Uint8:
+		return 8, false
+	case types.Uint16:
+		return 16, false
+	case types.Uint32:
+		return 32, false
+	}
+	return 0, false
+}
+
+// wrapInt truncates the integer x to the size of t
+func wrapInt(x string, t types.Type) string {
+	bits, signed := intBits(t)
+	if bits == 0 {
+		return x
+	}
+	if strings.Contains(x, " ") {
+		x = "(" + x + ")"
+	}
+	switch {
+	case bits == 32 && signed:
+		return "(" + x + " | 0)"
+	case bits == 32:
+		return "(" + x + " >>> 0)"
+	case signed:
+		return fmt.Sprintf("((%s << %d) >> %d)", x, 32-bits, 32-bits)
+	}
+	return fmt.Sprintf("(%s & 0x%x)", x, 1<<bits-1)
+}
+
+// fitsInt reports whether every value of the integer type from
+// is a value of the integer type to
+func fitsInt(from types.Type, to types.Type) bool {
+	fromBits, fromSigned := intBits(from)
+	toBits, toSigned := intBits(to)
+	switch {
+	case fromBits == 0 || toBits == 0:
+		return toBits == 0
+	case fromSigned == toSigned:
+		return fromBits <= toBits
+	}
+	return !fromSigned && fromBits < toBits
+}
+
+// operand translates an operand of op, adding parentheses where
+// JS would group it differently than Go
+func (c *Conv) operand(e ast.Expr, op token.Token, right bool) string {
+	s := c.expr(e)
+	inner, ok := e.(*ast.BinaryExpr)
+	if !ok || c.typesInfo.Types[e].Value != nil {
+		return s
+	}
+	if inner.Op == token.QUO && isInteger(c.typesInfo.TypeOf(inner)) {
+		return s
+	}
+	p, innerP := jsPrecedence[op], jsPrecedence[inner.Op]
+	if op == token.AND_NOT && right {
+		// the operand of ~
+		p = 11
+	}
+	if innerP < p || right && innerP == p {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+func (c *Conv) unaryExpr(f *ast.UnaryExpr) string {
+	switch f.Op {
+	case token.AND:
+		return c.pointer(f, c.typesInfo.TypeOf(f.X), f.X)
+	case token.ADD:
+		return c.expr(f.X)
+	case token.ARROW:
+		return c.expr(f.X) + ".recv()"
+	case token.SUB, token.NOT, token.XOR:
+		x := c.expr(f.X)
+		switch f.X.(type) {
+		case *ast.BinaryExpr, *ast.UnaryExpr:
+			x = "(" + x + ")"
+		}
+		t := c.typesInfo.TypeOf(f)
+		switch f.Op {
+		case token.SUB:
+			return wrapInt("-"+x, t)
+		case token.XOR:
+			if _, signed := intBits(t); !signed {
+				return wrapInt("~"+x, t)
+			}
+			return "~" + x
+		}
+		return "!" + x
+	}
+	return c.unsupported(f, "operator %s", f.Op)
+}
+
+func (c *Conv) selectorExpr(f *ast.SelectorExpr) string {
+	if s := c.typesInfo.Selections[f]; s != nil {
+		switch s.Kind() {
+		case types.FieldVal:
+			return c.selection(f.X, s)
+		case types.MethodVal:
+			// a method value is bound to its receiver
+			recv := c.selectionRecv(f.X, s)
+			if fn := s.Obj().(*types.Func); c.isMethodFunc(fn) {
+				return fmt.Sprintf("(...args: any[]) => %s(%s, ...args)", c.methodFunc(fn), recv)
+			}
+			return fmt.Sprintf("%s.%s.bind(%s)", recv, f.Sel.Name, recv)
+		}
+	}
+	if id, ok := f.X.(*ast.Ident); ok {
+		if pkg, ok := c.typesInfo.Uses[id].(*types.PkgName); ok {
+			if pkg.Imported().Path() != "fmt" || !fmtFuncs[f.Sel.Name] {
+				return c.unsupported(f, "%s.%s", pkg.Imported().Path(), f.Sel.Name)
+			}
+			return c.importRuntime("fmt") + "." + f.Sel.Name
+		}
+	}
+	return c.expr(f.X) + "." + f.Sel.Name
+}
+
+func (c *Conv) indexExpr(f *ast.IndexExpr) string {
+	t := c.typesInfo.TypeOf(f.X)
+	switch {
+	case isMap(t), isSlice(t):
+		return fmt.Sprintf("%s.get(%s)", c.expr(f.X), c.expr(f.Index))
+	case isString(t):
+		return fmt.Sprintf("%s.byteAt(%s, %s)", c.runtime(), c.expr(f.X), c.expr(f.Index))
+	}
+	return fmt.Sprintf("%s[%s]", c.expr(f.X), c.expr(f.Index))
+}
+
+// indexTarget splits a map or slice index expression, which
+// must be assigned through set
+func (c *Conv) indexTarget(e ast.Expr) (x string, key string, ok bool) {
+	index, ok := unparen(e).(*ast.IndexExpr)
+	if !ok {
+		return "", "", false
+	}
+	if t := c.typesInfo.TypeOf(index.X); !isMap(t) && !isSlice(t) {
+		return "", "", false
+	}
+	return c.expr(index.X), c.expr(index.Index), true
+}
+
+func (c *Conv) compositeLit(f *ast.CompositeLit) string {
+	switch t := c.typesInfo.TypeOf(f).Underlying().(type) {
+	case *types.Slice:
+		return fmt.Sprintf("new %s([%s])", c.tsType(t), strings.Join(c.elems(f), ", "))
+	case *types.Array:
+		// missing elements are zero
+		elems := c.elems(f)
+		for int64(len(elems)) < t.Len() {
+			elems = append(elems, c.zeroValue(t.Elem()))
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case *types.Map:
+		entries := make([]string, 0, len(f.Elts))
+		for _, elt := range f.Elts {
+			kv := elt.(*ast.KeyValueExpr)
+			entries = append(entries, fmt.Sprintf("[%s, %s]", c.valueExpr(kv.Key), c.valueExpr(kv.Value)))
+		}
+		return c.newMap(t, "["+strings.Join(entries, ", ")+"]")
+	case *types.Struct:
+		return c.structLit(f, t)
+	}
+	return c.unsupported(f, "composite literal of %s", c.typesInfo.TypeOf(f))
+}
+
+// structLit translates a struct literal to a constructor call
+// taking the fields by name
+func (c *Conv) structLit(f *ast.CompositeLit, st *types.Struct) string {
+	var fields []string
+	for i, elt := range f.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			fields = append(fields, kv.Key.(*ast.Ident).Name+": "+c.valueExpr(kv.Value))
+		} else {
+			fields = append(fields, st.Field(i).Name()+": "+c.valueExpr(elt))
+		}
+	}
+	named, ok := c.typesInfo.TypeOf(f).(*types.Named)
+	switch {
+	case !ok:
+		// anonymous structs are plain objects with all fields
+		return c.structObject(st, fields)
+	case named.Obj().Pkg() != c.typePkg:
+		return "{ " + strings.Join(fields, ", ") + " }"
+	case len(fields) == 0:
+		return "new " + c.name(named.Obj()) + "()"
+	}
+	return fmt.Sprintf("new %s({ %s })", c.name(named.Obj()), strings.Join(fields, ", "))
+}
+
+// structObject returns an object literal of the anonymous struct
+// st, the fields not in values are zero
+func (c *Conv) structObject(st *types.Struct, values []string) string {
+	fields := make([]string, 0, st.NumFields())
+	for i := 0; i < st.NumFields(); i++ {
+		name := st.Field(i).Name()
+		value := c.zeroValue(st.Field(i).Type())
+		for _, v := range values {
+			if strings.HasPrefix(v, name+": ") {
+				value = strings.TrimPrefix(v, name+": ")
+			}
+		}
+		fields = append(fields, name+": "+value)
+	}
+	return "{ " + strings.Join(fields, ", ") + " }"
+}
+
+func (c *Conv) elems(f *ast.CompositeLit) []string {
+	elems := make([]string, 0, len(f.Elts))
+	for _, elt := range f.Elts {
+		elems = append(elems, c.valueExpr(elt))
+	}
+	return elems
+}
+
+// newMap creates a map with the entries, keys of struct and
+// array types are compared by value
+func (c *Conv) newMap(t *types.Map, entries string) string {
+	args := []string{c.zeroValue(t.Elem())}
+	if isValue(t.Key()) {
+		args = append(args, entries, "true")
+	} else if entries != "[]" {
+		args = append(args, entries)
+	}
+	return fmt.Sprintf("new %s(%s)", c.tsType(t), strings.Join(args, ", "))
+}
+
+func (c *Conv) callExpr(f *ast.CallExpr) string {
+	if tv := c.typesInfo.Types[f.Fun]; tv.IsType() {
+		return c.conversion(f.Args[0], tv.Type)
+	}
+	if name := c.builtin(f.Fun); name != "" {
+		return c.builtinCall(name, f)
+	}
+
+	if sel, ok := f.Fun.(*ast.SelectorExpr); ok {
+		if s := c.typesInfo.Selections[sel]; s != nil && s.Kind() == types.MethodVal {
+			return c.methodCall(f, sel, s)
+		}
+		if fn, ok := c.typesInfo.Uses[sel.Sel].(*types.Func); ok && fn.Pkg() != nil && fn.Pkg().Path() == "fmt" && fmtFuncs[fn.Name()] {
+			return c.fmtCall(f, fn)
+		}
+	}
+	return fmt.Sprintf("%s(%s)", c.expr(f.Fun), strings.Join(c.callArgs(f), ", "))
+}
+
+// fmtFuncs are the functions of the fmt runtime module
+var fmtFuncs = map[string]bool{
+	"Sprintf":  true,
+	"Sprint":   true,
+	"Sprintln": true,
+	"Printf":   true,
+	"Print":    true,
+	"Println":  true,
+	"Errorf":   true,
+}
+
+// fmtCall translates calls to the fmt runtime module. The operands
+// are passed along with their Go types unless the types can be
+// told from the JS values, or the operands are the spread results
+// of a call.
+func (c *Conv) fmtCall(f *ast.CallExpr, fn *types.Func) string {
+	args := c.args(f)
+	sig := fn.Type().(*types.Signature)
+	var spread bool
+	if len(f.Args) == 1 {
+		_, spread = c.typesInfo.TypeOf(f.Args[0]).(*types.Tuple)
+	}
+	if sig.Variadic() && !f.Ellipsis.IsValid() && !spread {
+		for i := sig.Params().Len() - 1; i < len(args); i++ {
+			args[i] = c.fmtOperand(f.Args[i], args[i])
+		}
+	}
+	return fmt.Sprintf("%s.%s(%s)", c.importRuntime("fmt"), fn.Name(), strings.Join(args, ", "))
+}
+
+func (c *Conv) fmtOperand(e ast.Expr, code string) string {
+	t := types.Default(c.typesInfo.TypeOf(e))
+	switch u := t.Underlying().(type) {
+	case *types.Interface:
+		return code
+	case *types.Basic:
+		if t == u && (u.Kind() == types.Int || u.Kind() == types.String || u.Kind() == types.Bool || u.Kind() == types.UntypedNil) {
+			return code
+		}
+	}
+	typ := strconv.Quote(goType(t))
+	if _, ok := t.(*types.Named); ok && !isStruct(t) {
+		typ += ", " + strconv.Quote(goType(t.Underlying()))
+	}
+	return fmt.Sprintf("%s.typed(%s, %s)", c.importRuntime("fmt"), code, typ)
+}
+
+// goType returns the name of t the fmt runtime shows, like main.Point
+func goType(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		return p.Name()
+	})
+}
+
+// args translates the arguments of a call to a JS function,
+// spreading multiple results and variadic slices
+func (c *Conv) args(f *ast.CallExpr) []string {
+	if len(f.Args) == 1 {
+		if tuple, ok := c.typesInfo.TypeOf(f.Args[0]).(*types.Tuple); ok && tuple.Len() > 1 {
+			return []string{"..." + c.expr(f.Args[0])}
+		}
+	}
+	args := make([]string, 0, len(f.Args))
+	for _, arg := range f.Args {
+		args = append(args, c.expr(arg))
+	}
+	if f.Ellipsis.IsValid() && len(args) > 0 {
+		args[len(args)-1] = "..." + args[len(args)-1]
+	}
+	return args
+}
+
+// builtin returns the name of the builtin function fun refers to
+func (c *Conv) builtin(fun ast.Expr) string {
+	id, ok := unparen(fun).(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	if b, ok := c.typesInfo.Uses[id].(*types.Builtin); ok {
+		return b.Name()
+	}
+	return ""
+}
+
+func (c *Conv) builtinCall(name string, f *ast.CallExpr) string {
+	// the first argument is a type
+	switch name {
+	case "make":
+		return c.makeCall(f)
+	case "new":
+		t := c.typesInfo.TypeOf(f.Args[0])
+		if !isValue(t) {
+			return c.unsupported(f, "pointer to %s", goType(t))
+		}
+		return c.zeroValue(t)
+	}
+
+	if name == "append" {
+		return c.appendCall(f)
+	}
+	args := c.args(f)
+	switch name {
+	case "len", "cap":
+		switch t := c.typesInfo.TypeOf(f.Args[0]).Underlying().(type) {
+		case *types.Map, *types.Slice:
+			return fmt.Sprintf("%s.%s(%s)", c.runtime(), name, args[0])
+		case *types.Basic:
+			if t.Info()&types.IsString != 0 {
+				return fmt.Sprintf("%s.len(%s)", c.runtime(), args[0])
+			}
+		case *types.Chan:
+			if name == "cap" {
+				return args[0] + ".cap"
+			}
+		}
+		return args[0] + ".length"
+	case "copy":
+		src := args[1]
+		if isString(c.typesInfo.TypeOf(f.Args[1])) {
+			src = c.conversion(f.Args[1], c.typesInfo.TypeOf(f.Args[0]))
+		}
+		return fmt.Sprintf("%s.copy(%s, %s)", c.runtime(), args[0], src)
+	case "close":
+		return args[0] + ".close()"
+	case "delete":
+		return fmt.Sprintf("%s.delete(%s)", args[0], args[1])
+	case "min", "max":
+		if isString(c.typesInfo.TypeOf(f)) {
+			break
+		}
+		return fmt.Sprintf("Math.%s(%s)", name, strings.Join(args, ", "))
+	case "print", "println":
+		return fmt.Sprintf("console.log(%s)", strings.Join(args, ", "))
+	}
+	return c.unsupported(f, "builtin %s", name)
+}
+
+// appendCall appends to the slice in place if it has room, as Go
+// does, the appended values are copied
+func (c *Conv) appendCall(f *ast.CallExpr) string {
+	args := []string{c.expr(f.Args[0])}
+	if f.Ellipsis.IsValid() {
+		rest := f.Args[1]
+		if isString(c.typesInfo.TypeOf(rest)) {
+			args = append(args, fmt.Sprintf("...new TextEncoder().encode(%s)", c.expr(rest)))
+		} else {
+			args = append(args, fmt.Sprintf("...%s.values(%s)", c.runtime(), c.expr(rest)))
+		}
+	} else {
+		for _, arg := range f.Args[1:] {
+			args = append(args, c.valueExpr(arg))
+		}
+	}
+	return fmt.Sprintf("%s.append(%s)", c.runtime(), strings.Join(args, ", "))
+}
+
+func (c *Conv) makeCall(f *ast.CallExpr) string {
+	t := c.typesInfo.TypeOf(f.Args[0])
+	var size string
+	if len(f.Args) > 1 {
+		size = c.expr(f.Args[1])
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Map:
+		return c.newMap(u, "[]")
+	case *types.Chan:
+		if size == "" {
+			size = "0"
+		}
+		return fmt.Sprintf("new %s(%s, %s)", c.tsType(t), size, c.zeroValue(u.Elem()))
+	case *types.Slice:
+		// the backing array has the capacity
+		if len(f.Args) > 2 {
+			array := fmt.Sprintf("Array.from({ length: %s }, () => %s)", c.expr(f.Args[2]), c.zeroValue(u.Elem()))
+			return fmt.Sprintf("new %s(%s, 0, %s)", c.tsType(t), array, size)
+		}
+		return fmt.Sprintf("new %s(Array.from({ length: %s }, () => %s))", c.tsType(t), size, c.zeroValue(u.Elem()))
+	}
+	return ""
+}
+
+// conversion translates the conversion of x to type t
+func (c *Conv) conversion(x ast.Expr, t types.Type) string {
+	from := c.typesInfo.TypeOf(x)
+	s := c.expr(x)
+	switch {
+	case isString(t) && isInteger(from):
+		return fmt.Sprintf("String.fromCodePoint(%s)", s)
+	case isString(t) && isByteSlice(from):
+		return fmt.Sprintf("new TextDecoder().decode(Uint8Array.from(%s.values(%s)))", c.runtime(), s)
+	case isString(t) && isRuneSlice(from):
+		return fmt.Sprintf("String.fromCodePoint(...%s.values(%s))", c.runtime(), s)
+	case isByteSlice(t) && isString(from):
+		return fmt.Sprintf("new %s(Array.from(new TextEncoder().encode(%s)))", c.tsType(t), s)
+	case isRuneSlice(t) && isString(from):
+		return fmt.Sprintf("new %s(Array.from(%s, (r) => r.codePointAt(0) as number))", c.tsType(t), s)
+	case isInteger(t) && isFloat(from):
+		return wrapInt(fmt.Sprintf("Math.trunc(%s)", s), t)
+	case isInteger(t) && isInteger(from) && !fitsInt(from, t):
+		return wrapInt(s, t)
+	}
+	return s
+}
+
+func isByteSlice(t types.Type) bool {
+	s, ok := t.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+	b, ok := s.Elem().Underlying().(*types.Basic)
+	return ok && b.Kind() == types.Byte
+}
+
+func isRuneSlice(t types.Type) bool {
+	s, ok := t.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+	b, ok := s.Elem().Underlying().(*types.Basic)
+	return ok && b.Kind() == types.Rune
+}
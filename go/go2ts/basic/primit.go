@@ -1,37 +1,76 @@
 package basic
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/xhd2015/less-gen/strcase"
 	"golang.org/x/tools/go/packages"
 )
 
-func processPkg(fset *token.FileSet, pkg *packages.Package) string {
+func processPkg(fset *token.FileSet, pkg *packages.Package) (string, error) {
 	c := &Conv{
 		fset:      fset,
 		pkg:       pkg,
 		typePkg:   pkg.Types,
 		typesInfo: pkg.TypesInfo,
+		names:     make(map[types.Object]string),
+		mutated:   make(map[types.Object]bool),
+		captured:  make(map[types.Object]bool),
+		methods:   make(map[*types.TypeName][]*ast.FuncDecl),
+		imports:   make(map[string]bool),
+	}
+	c.pushScope()
+	for _, name := range jsGlobals {
+		c.scopes[0][name] = true
+	}
+	c.pushScope()
+	// the fmt runtime module is imported as fmt
+	c.scopes[1]["fmt"] = true
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		c.declare(scope.Lookup(name))
+	}
+	for _, file := range pkg.Syntax {
+		c.findMutated(file)
 	}
 
 	var codes []string
+	for _, spec := range c.findTypes(pkg.Syntax) {
+		if code := c.typeSpec(spec, false); code != "" {
+			codes = append(codes, code)
+		}
+	}
 	for _, file := range pkg.Syntax {
 		code := c.file(file)
 		codes = append(codes, code)
 	}
 
 	jointCode := strings.Join(codes, "\n")
+	modules := make([]string, 0, len(c.imports))
+	for module := range c.imports {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	imports := make([]string, 0, len(modules)+1)
+	for _, module := range modules {
+		imports = append(imports, fmt.Sprintf("import * as %s from %q;", module, runtimeModule+"/"+module))
+	}
+	jointCode = strings.Join(append(imports, jointCode), "\n")
 
 	if pkg.Name == "main" {
 		jointCode = jointCode + "\nmain()"
 	}
-	return jointCode
+	if len(c.errs) > 0 {
+		return "", errors.New(strings.Join(c.errs, "\n"))
+	}
+	return jointCode, nil
 }
 
 type Conv struct {
@@ -39,6 +78,41 @@ type Conv struct {
 	pkg       *packages.Package
 	typePkg   *types.Package
 	typesInfo *types.Info
+
+	names    map[types.Object]string // TS names of the declared objects
+	scopes   []map[string]bool       // TS names declared in the enclosing blocks
+	mutated  map[types.Object]bool   // variables assigned after declaration
+	captured map[types.Object]bool   // variables referred to by closures
+	sigs     []*types.Signature      // signatures of the enclosing functions
+
+	methods map[*types.TypeName][]*ast.FuncDecl // methods of the struct types
+
+	imports map[string]bool // runtime modules used by the code
+
+	errs []string // constructs without a translation
+}
+
+// runtimeModule is the import path of the TS modules in
+// go2ts/runtime, which translated code relies on
+const runtimeModule = "go2ts/runtime"
+
+// runtime returns the namespace of the go runtime module. Go
+// names cannot clash with it, go being a keyword.
+func (c *Conv) runtime() string {
+	return c.importRuntime("go")
+}
+
+// importRuntime imports the runtime module and returns its namespace
+func (c *Conv) importRuntime(module string) string {
+	c.imports[module] = true
+	return module
+}
+
+// unsupported records that node has no translation, so the package
+// fails to translate instead of missing code
+func (c *Conv) unsupported(node ast.Node, format string, args ...interface{}) string {
+	c.errs = append(c.errs, fmt.Sprintf("%s: unsupported %s", c.fset.Position(node.Pos()), fmt.Sprintf(format, args...)))
+	return ""
 }
 
 func (c *Conv) file(f *ast.File) string {
@@ -47,28 +121,39 @@ func (c *Conv) file(f *ast.File) string {
 func (c *Conv) decls(decls []ast.Decl) string {
 	var declCode []string
 	for _, decl := range decls {
+		var code string
 		switch d := decl.(type) {
 		case *ast.GenDecl:
-			declCode = append(declCode, c.genDecl(d))
+			code = c.genDecl(d)
 		case *ast.FuncDecl:
-			declCode = append(declCode, c.funcDecl(d))
+			code = c.funcDecl(d)
 		default:
-			fmt.Printf("decl %T %v\n", decl, decl)
+			c.unsupported(decl, "declaration %T", decl)
+		}
+		if code != "" {
+			declCode = append(declCode, code)
 		}
 	}
 	return strings.Join(declCode, "\n")
 }
 
 func (c *Conv) genDecl(g *ast.GenDecl) string {
+	var codes []string
 	switch g.Tok {
 	case token.IMPORT:
 		for _, spec := range g.Specs {
 			c.spec(spec)
 		}
+	case token.VAR, token.CONST:
+		for _, spec := range g.Specs {
+			codes = append(codes, c.valueSpec(g.Tok, spec.(*ast.ValueSpec), true))
+		}
+	case token.TYPE:
+		// translated ahead of the other declarations
 	default:
-		fmt.Printf("token %s\n", g.Tok)
+		c.unsupported(g, "declaration %s", g.Tok)
 	}
-	return ""
+	return strings.Join(codes, "\n")
 }
 
 func (c *Conv) spec(s ast.Spec) string {
@@ -77,108 +162,158 @@ func (c *Conv) spec(s ast.Spec) string {
 		return c.importSpec(s)
 
 	default:
-		fmt.Printf("spec: %T %v\n", s, s)
+		c.unsupported(s, "declaration %T", s)
 	}
 	return ""
 }
 
+// importSpec translates nothing, fmt is provided by the runtime and
+// other packages can only be used for their types and constants,
+// see selectorExpr
 func (c *Conv) importSpec(s *ast.ImportSpec) string {
-	fmt.Printf("import %s\n", s.Path.Value)
 	return ""
 }
 
 func (c *Conv) funcDecl(f *ast.FuncDecl) string {
-	var name string
-	if f.Name != nil {
-		name = f.Name.Name
+	if c.classOf(f) != nil {
+		// translated in the class
+		return ""
+	}
+	obj := c.typesInfo.Defs[f.Name]
+	name := c.name(obj)
+	if f.Recv != nil {
+		name = c.methodFunc(obj.(*types.Func))
 	}
-
-	exported := isExported(name)
 
 	var modifier string
-	if exported {
+	if isExported(name) {
 		modifier = "export "
 	}
-	var body string
-	if f.Body != nil {
-		body = c.blockStmt(f.Body)
-	} else {
-		body = "throw \"implementation not found\""
-	}
-	return fmt.Sprintf("%sfunction %s(){\n%s\n}", modifier, name, body)
+	sig := obj.Type().(*types.Signature)
+	params, body := c.funcBody(sig, f.Body)
+	return fmt.Sprintf("%sfunction %s(%s)%s {\n%s\n}", modifier, name, params, c.resultAnnot(sig), body)
 }
 
-func (c *Conv) blockStmt(f *ast.BlockStmt) string {
-	stmts := make([]string, 0, len(f.List))
-	for _, stmt := range f.List {
-		stmts = append(stmts, c.stmt(stmt))
-	}
-	return strings.Join(stmts, "\n")
+func (c *Conv) funcLit(f *ast.FuncLit) string {
+	sig := c.typesInfo.TypeOf(f).(*types.Signature)
+	params, body := c.funcBody(sig, f.Body)
+	return fmt.Sprintf("(%s)%s => {\n%s\n}", params, c.resultAnnot(sig), body)
 }
 
-func (c *Conv) stmt(f ast.Stmt) string {
-	switch f := f.(type) {
-	case *ast.ExprStmt:
-		return c.exprStmt(f)
-	default:
-		fmt.Printf("stmt: %T %v\n", f, f)
+// funcBody translates the parameters and the body of a function,
+// which share one scope in Go
+func (c *Conv) funcBody(sig *types.Signature, body *ast.BlockStmt) (string, string) {
+	c.pushScope()
+	defer c.popScope()
+	c.sigs = append(c.sigs, sig)
+	defer func() {
+		c.sigs = c.sigs[:len(c.sigs)-1]
+	}()
+
+	var stmts []string
+	params := make([]string, 0, sig.Params().Len()+1)
+	if recv := sig.Recv(); recv != nil {
+		if c.recvClass(recv) != nil {
+			stmts = append(stmts, c.receiver(recv, body))
+		} else {
+			// the receiver of a method function
+			params = append(params, c.declare(recv)+c.annot(recv.Type()))
+		}
 	}
-	return ""
+	for i := 0; i < sig.Params().Len(); i++ {
+		v := sig.Params().At(i)
+		var name string
+		if v.Name() == "" || v.Name() == "_" {
+			name = c.fresh(fmt.Sprintf("_%d", i))
+		} else {
+			name = c.declare(v)
+		}
+		params = append(params, name+c.annot(v.Type()))
+	}
+	if body == nil {
+		return strings.Join(params, ", "), `throw new Error("implementation not found")`
+	}
+
+	res := sig.Results()
+	for i := 0; i < res.Len(); i++ {
+		v := res.At(i)
+		if v.Name() != "" && v.Name() != "_" {
+			stmts = append(stmts, fmt.Sprintf("let %s%s = %s;", c.declare(v), c.annot(v.Type()), c.zeroValue(v.Type())))
+		}
+	}
+	stmts = append(stmts, c.stmts(body.List)...)
+	return strings.Join(params, ", "), joinCode(stmts)
 }
 
-func (c *Conv) exprStmt(f *ast.ExprStmt) string {
-	return c.expr(f.X)
+func (c *Conv) blockStmt(f *ast.BlockStmt) string {
+	return c.blockWith("", f)
 }
 
-func (c *Conv) expr(f ast.Expr) string {
-	switch f := f.(type) {
-	case *ast.CallExpr:
-		return c.callExpr(f)
-	case *ast.BasicLit:
-		return c.basicLit(f)
-	default:
-		fmt.Printf("expr: %T %v\n", f, f)
+// blockWith translates a block starting with the statement head
+func (c *Conv) blockWith(head string, f *ast.BlockStmt) string {
+	c.pushScope()
+	defer c.popScope()
+	stmts := c.stmts(f.List)
+	if head != "" {
+		stmts = append([]string{head}, stmts...)
 	}
-	return ""
+	return "{\n" + strings.Join(stmts, "\n") + "\n}"
 }
 
-func (c *Conv) basicLit(f *ast.BasicLit) string {
-	switch f.Kind {
-	case token.STRING:
-		return f.Value
-	default:
-		return f.Value
+func (c *Conv) stmts(list []ast.Stmt) []string {
+	stmts := make([]string, 0, len(list))
+	for _, stmt := range list {
+		if code := c.stmt(stmt); code != "" {
+			stmts = append(stmts, code)
+		}
 	}
+	return stmts
 }
-func (c *Conv) callExpr(f *ast.CallExpr) string {
-	var fn string
-	if f.Fun != nil {
-		var def types.Object
-		switch t := f.Fun.(type) {
-		case *ast.Ident:
-			def = c.typesInfo.Uses[t]
-		case *ast.SelectorExpr:
-			def = c.typesInfo.Uses[t.Sel]
-		}
-		if def == nil {
-			fmt.Printf("def not found\n")
-		} else {
-			fmt.Printf("def found: pkg=%s, name=%s\n", def.Pkg().Path(), def.Name())
-		}
 
-		if def.Pkg().Path() == "fmt" && (def.Name() == "Printf" || def.Name() == "Println") {
-			fn = "console.log"
-		} else {
-			fn = c.expr(f.Fun)
-		}
+func (c *Conv) stmt(f ast.Stmt) string {
+	switch f := f.(type) {
+	case *ast.ExprStmt:
+		return c.exprStmt(f)
+	case *ast.DeclStmt:
+		return c.declStmt(f)
+	case *ast.AssignStmt:
+		return c.assignStmt(f)
+	case *ast.IncDecStmt:
+		return c.incDecStmt(f)
+	case *ast.ReturnStmt:
+		return c.returnStmt(f)
+	case *ast.BlockStmt:
+		return c.blockStmt(f)
+	case *ast.IfStmt:
+		return c.ifStmt(f)
+	case *ast.ForStmt:
+		return c.forStmt(f, "")
+	case *ast.RangeStmt:
+		return c.rangeStmt(f, "")
+	case *ast.SwitchStmt:
+		return c.switchStmt(f)
+	case *ast.LabeledStmt:
+		return c.labeledStmt(f)
+	case *ast.BranchStmt:
+		return c.branchStmt(f)
+	case *ast.SendStmt:
+		return c.sendStmt(f)
+	case *ast.SelectStmt:
+		return c.selectStmt(f)
+	case *ast.EmptyStmt:
+		return ""
 	}
+	return c.unsupported(f, "statement %T", f)
+}
 
-	var exprs []string
-	for _, arg := range f.Args {
-		expr := c.expr(arg)
-		exprs = append(exprs, expr)
+func (c *Conv) exprStmt(f *ast.ExprStmt) string {
+	if call, ok := f.X.(*ast.CallExpr); ok && c.builtin(call.Fun) == "panic" {
+		if isString(c.typesInfo.TypeOf(call.Args[0])) {
+			return fmt.Sprintf("throw new Error(%s);", c.expr(call.Args[0]))
+		}
+		return fmt.Sprintf("throw %s;", c.expr(call.Args[0]))
 	}
-	return fmt.Sprintf("%s(%s)", fn, strings.Join(exprs, ","))
+	return c.expr(f.X) + ";"
 }
 
 func isExported(name string) bool {
@@ -0,0 +1,159 @@
+package basic
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// jsGlobals are referenced by the translated code, Go names
+// equal to them are renamed so the globals stay reachable
+var jsGlobals = []string{
+	"Array", "Error", "Infinity", "JSON", "Map", "Math", "NaN",
+	"Number", "Object", "String", "Symbol", "TextDecoder",
+	"TextEncoder", "Uint8Array", "console", "undefined",
+}
+
+// tsReserved are the TypeScript reserved words that
+// are valid Go identifiers
+var tsReserved = map[string]bool{
+	"arguments": true, "await": true, "catch": true, "class": true,
+	"debugger": true, "delete": true, "do": true, "enum": true,
+	"eval": true, "export": true, "extends": true, "finally": true,
+	"function": true, "implements": true, "in": true, "instanceof": true,
+	"let": true, "new": true, "null": true, "private": true,
+	"protected": true, "public": true, "static": true, "super": true,
+	"this": true, "throw": true, "try": true, "typeof": true,
+	"void": true, "while": true, "with": true, "yield": true,
+}
+
+func (c *Conv) pushScope() {
+	c.scopes = append(c.scopes, make(map[string]bool))
+}
+
+func (c *Conv) popScope() {
+	c.scopes = c.scopes[:len(c.scopes)-1]
+}
+
+// declare assigns the TS name of obj in the current block. A name
+// visible from an enclosing block gets a numeric suffix: TS would
+// otherwise reject reading the shadowed variable before the new
+// one is initialized, which Go allows in `x := x + 1`.
+func (c *Conv) declare(obj types.Object) string {
+	name := c.fresh(obj.Name())
+	c.names[obj] = name
+	return name
+}
+
+// fresh reserves a name based on base in the current block
+func (c *Conv) fresh(base string) string {
+	if tsReserved[base] {
+		base += "_"
+	}
+	name := base
+	for i := 1; c.visible(name); i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	c.scopes[len(c.scopes)-1][name] = true
+	return name
+}
+
+func (c *Conv) visible(name string) bool {
+	for _, scope := range c.scopes {
+		if scope[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// name returns the TS name of obj
+func (c *Conv) name(obj types.Object) string {
+	if name, ok := c.names[obj]; ok {
+		return name
+	}
+	if tsReserved[obj.Name()] {
+		return obj.Name() + "_"
+	}
+	return obj.Name()
+}
+
+// findMutated records the variables assigned after their
+// declaration, the others are declared with const
+func (c *Conv) findMutated(node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				c.markMutated(lhs)
+			}
+		case *ast.IncDecStmt:
+			c.markMutated(n.X)
+		case *ast.RangeStmt:
+			if n.Tok == token.ASSIGN {
+				c.markMutated(n.Key)
+				c.markMutated(n.Value)
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				c.markMutated(n.X)
+			}
+		case *ast.FuncLit:
+			c.markCaptured(n)
+		}
+		return true
+	})
+}
+
+// markCaptured records the variables the closure f refers to
+// from the enclosing functions
+func (c *Conv) markCaptured(f *ast.FuncLit) {
+	ast.Inspect(f.Body, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if obj, ok := c.typesInfo.Uses[id].(*types.Var); ok && obj.Pos() < f.Pos() {
+			c.captured[obj] = true
+		}
+		return true
+	})
+}
+
+// markMutated marks the variable e refers to, identifiers declared
+// by e itself are in Defs rather than Uses
+func (c *Conv) markMutated(e ast.Expr) {
+	id, ok := unparen(e).(*ast.Ident)
+	if !ok {
+		return
+	}
+	if obj, ok := c.typesInfo.Uses[id].(*types.Var); ok {
+		c.mutated[obj] = true
+	}
+}
+
+// declKeyword returns const if none of objs is mutated
+func (c *Conv) declKeyword(objs ...types.Object) string {
+	for _, obj := range objs {
+		if obj != nil && c.mutated[obj] {
+			return "let"
+		}
+	}
+	return "const"
+}
+
+func unparen(e ast.Expr) ast.Expr {
+	for {
+		p, ok := e.(*ast.ParenExpr)
+		if !ok {
+			return e
+		}
+		e = p.X
+	}
+}
+
+func isBlank(e ast.Expr) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == "_"
+}
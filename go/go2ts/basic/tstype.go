@@ -0,0 +1,218 @@
+package basic
+
+import (
+	"fmt"
+	"go/constant"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// tsType returns the TypeScript type of t
+func (c *Conv) tsType(t types.Type) string {
+	switch t := t.(type) {
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return "boolean"
+		case t.Info()&types.IsString != 0:
+			return "string"
+		case t.Info()&types.IsNumeric != 0:
+			return "number"
+		case t.Kind() == types.UntypedNil:
+			return "null"
+		}
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() == nil && obj.Name() == "error" {
+			return "Error | null"
+		}
+		if _, ok := t.Underlying().(*types.Struct); ok {
+			if obj.Pkg() == c.typePkg {
+				return c.name(obj)
+			}
+			// structs of other packages are not translated
+			return "any"
+		}
+		return c.tsType(t.Underlying())
+	case *types.Pointer:
+		if isStruct(t.Elem()) {
+			return c.tsType(t.Elem()) + " | null"
+		}
+	case *types.Slice:
+		return fmt.Sprintf("%s.Slice<%s>", c.runtime(), c.tsType(t.Elem()))
+	case *types.Array:
+		return c.elemType(t.Elem()) + "[]"
+	case *types.Map:
+		return fmt.Sprintf("%s.Map<%s, %s>", c.runtime(), c.tsType(t.Key()), c.tsType(t.Elem()))
+	case *types.Signature:
+		return fmt.Sprintf("(%s) => %s", c.paramTypes(t), c.resultType(t))
+	case *types.Chan:
+		return fmt.Sprintf("%s.Chan<%s>", c.runtime(), c.tsType(t.Elem()))
+	case *types.Struct:
+		fields := make([]string, 0, t.NumFields())
+		for i := 0; i < t.NumFields(); i++ {
+			fields = append(fields, t.Field(i).Name()+c.annot(t.Field(i).Type()))
+		}
+		return "{ " + strings.Join(fields, "; ") + " }"
+	}
+	return "any"
+}
+
+// elemType returns the type of t as an array element
+func (c *Conv) elemType(t types.Type) string {
+	s := c.tsType(t)
+	if strings.Contains(s, " ") {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// resultType returns the return type of sig, multiple
+// results are returned as a tuple
+func (c *Conv) resultType(sig *types.Signature) string {
+	res := sig.Results()
+	switch res.Len() {
+	case 0:
+		return "void"
+	case 1:
+		return c.tsType(res.At(0).Type())
+	}
+	list := make([]string, 0, res.Len())
+	for i := 0; i < res.Len(); i++ {
+		list = append(list, c.tsType(res.At(i).Type()))
+	}
+	return "[" + strings.Join(list, ", ") + "]"
+}
+
+func (c *Conv) paramTypes(sig *types.Signature) string {
+	params := sig.Params()
+	list := make([]string, 0, params.Len())
+	for i := 0; i < params.Len(); i++ {
+		v := params.At(i)
+		name := v.Name()
+		if name == "" || name == "_" {
+			name = fmt.Sprintf("_%d", i)
+		}
+		if sig.Variadic() && i == params.Len()-1 {
+			name = "..." + name
+		}
+		list = append(list, name+": "+c.tsType(v.Type()))
+	}
+	return strings.Join(list, ", ")
+}
+
+// annot returns the type annotation of a declaration of type t
+func (c *Conv) annot(t types.Type) string {
+	return ": " + c.tsType(t)
+}
+
+// resultAnnot returns the return type annotation of sig,
+// functions without results have their type inferred
+func (c *Conv) resultAnnot(sig *types.Signature) string {
+	if sig.Results().Len() == 0 {
+		return ""
+	}
+	return ": " + c.resultType(sig)
+}
+
+// zeroValue returns the expression of the zero value of t
+func (c *Conv) zeroValue(t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false"
+		case u.Info()&types.IsString != 0:
+			return `""`
+		case u.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+	case *types.Struct:
+		named, ok := t.(*types.Named)
+		switch {
+		case !ok:
+			return c.structObject(u, nil)
+		case named.Obj().Pkg() == c.typePkg:
+			return "new " + c.name(named.Obj()) + "()"
+		}
+		return "{}"
+	case *types.Array:
+		return fmt.Sprintf("Array.from({ length: %d }, () => %s)", u.Len(), c.zeroValue(u.Elem()))
+	}
+	return "null"
+}
+
+// constValue returns the literal of a constant
+func (c *Conv) constValue(v constant.Value) string {
+	switch v.Kind() {
+	case constant.Bool:
+		return strconv.FormatBool(constant.BoolVal(v))
+	case constant.String:
+		return jsString(constant.StringVal(v))
+	case constant.Int:
+		return v.ExactString()
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return v.String()
+}
+
+// jsString quotes s as a JS string literal
+func jsString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f || r == 0x2028 || r == 0x2029 {
+				fmt.Fprintf(&sb, `\u%04x`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+func isStruct(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
+
+func isSlice(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Slice)
+	return ok
+}
+
+func isMap(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Map)
+	return ok
+}
+
+func isString(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsString != 0
+}
+
+func isInteger(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsInteger != 0
+}
+
+func isFloat(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsFloat != 0
+}
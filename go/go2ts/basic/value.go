@@ -0,0 +1,112 @@
+package basic
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// valueExpr translates e where Go copies its value. Structs and
+// arrays are JS objects, so they are cloned unless e is a new value.
+func (c *Conv) valueExpr(e ast.Expr) string {
+	code := c.expr(e)
+	if c.isFresh(e) {
+		return code
+	}
+	return c.cloneValue(code, c.typesInfo.TypeOf(e))
+}
+
+// resultExpr translates a returned value. Local variables are
+// not copied, nothing refers to them after the return.
+func (c *Conv) resultExpr(e ast.Expr) string {
+	if id, ok := unparen(e).(*ast.Ident); ok {
+		// receivers that are not copied are this
+		v, ok := c.typesInfo.Uses[id].(*types.Var)
+		if ok && v.Parent() != c.typePkg.Scope() && c.names[v] != "this" && !c.mutated[v] && !c.captured[v] {
+			return c.expr(e)
+		}
+	}
+	return c.valueExpr(e)
+}
+
+// isFresh reports whether e evaluates to a value no variable refers
+// to: literals, results of calls, which are copied by the return
+// statements, and received values
+func (c *Conv) isFresh(e ast.Expr) bool {
+	switch e := unparen(e).(type) {
+	case *ast.CompositeLit:
+		return true
+	case *ast.CallExpr:
+		return !c.typesInfo.Types[e.Fun].IsType()
+	case *ast.UnaryExpr:
+		return true
+	}
+	return false
+}
+
+// callArgs translates the arguments of a call to a Go function,
+// the variadic arguments are passed in a slice like Go does
+func (c *Conv) callArgs(f *ast.CallExpr) []string {
+	sig, _ := c.typesInfo.TypeOf(f.Fun).Underlying().(*types.Signature)
+	if len(f.Args) == 1 {
+		if tuple, ok := c.typesInfo.TypeOf(f.Args[0]).(*types.Tuple); ok && tuple.Len() > 1 {
+			return []string{"..." + c.expr(f.Args[0])}
+		}
+	}
+	args := make([]string, 0, len(f.Args))
+	if sig == nil || !sig.Variadic() || f.Ellipsis.IsValid() {
+		for _, arg := range f.Args {
+			args = append(args, c.valueExpr(arg))
+		}
+		return args
+	}
+	fixed := sig.Params().Len() - 1
+	for _, arg := range f.Args[:fixed] {
+		args = append(args, c.valueExpr(arg))
+	}
+	rest := f.Args[fixed:]
+	if len(rest) == 0 {
+		return append(args, "null")
+	}
+	values := make([]string, 0, len(rest))
+	for _, arg := range rest {
+		values = append(values, c.valueExpr(arg))
+	}
+	variadic := sig.Params().At(fixed).Type()
+	return append(args, fmt.Sprintf("new %s([%s])", c.tsType(variadic), strings.Join(values, ", ")))
+}
+
+// sliceExpr translates x[low:high:max], slices share the backing
+// array of x. Strings are sliced by bytes like Go does.
+func (c *Conv) sliceExpr(f *ast.SliceExpr) string {
+	x := c.expr(f.X)
+	var bounds []string
+	for _, e := range []ast.Expr{f.Low, f.High, f.Max} {
+		if e == nil {
+			bounds = append(bounds, "undefined")
+		} else {
+			bounds = append(bounds, c.expr(e))
+		}
+	}
+	for len(bounds) > 0 && bounds[len(bounds)-1] == "undefined" {
+		bounds = bounds[:len(bounds)-1]
+	}
+	if len(bounds) > 0 && bounds[0] == "undefined" {
+		bounds[0] = "0"
+	}
+	if isString(c.typesInfo.TypeOf(f.X)) {
+		return fmt.Sprintf("%s.substr(%s)", c.runtime(), strings.Join(append([]string{x}, bounds...), ", "))
+	}
+	return fmt.Sprintf("%s.slice(%s)", c.runtime(), strings.Join(append([]string{x}, bounds...), ", "))
+}
+
+// isValue reports whether values of t are compared and copied
+// by value in Go but not in JS
+func isValue(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Struct, *types.Array:
+		return true
+	}
+	return false
+}
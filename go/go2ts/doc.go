@@ -1,5 +1,21 @@
 // package go2ts transpile go code to
 // native ts code correspondent with
 // best effort.
+//
+// builtins and packages without a ts
+// counterpart, like channels and fmt, are
+// implemented by the modules in runtime/,
+// which the translated code imports as
+// go2ts/runtime/<module>.
+//
+// all numbers are ts numbers. integers up
+// to 32 bits wrap around like in go, wider
+// ones do not.
+//
+// code without a translation, like type
+// switches, goto, pointers to values other
+// than structs and arrays, and packages
+// other than fmt, is reported as an error
+// instead of being left out.
 
 package go2ts
@@ -0,0 +1,721 @@
+// Go fmt functions for the code translated with go2ts. JavaScript
+// values do not carry their Go types, so the translated code wraps
+// the arguments whose type cannot be told from the value in Typed.
+
+import { Map as GoMap, Slice } from "./go"
+
+// Typed is a value along with its Go type, the underlying type is
+// given for named types other than structs
+export class Typed {
+    constructor(
+        readonly value: unknown,
+        readonly type: string,
+        readonly underlying?: string,
+    ) {}
+}
+
+export function typed(value: unknown, type: string, underlying?: string): Typed {
+    return new Typed(value, type, underlying)
+}
+
+export function Sprintf(format: string, ...args: unknown[]): string {
+    const p = new Printer()
+    p.printf(format, args)
+    return p.buf
+}
+
+export function Sprint(...args: unknown[]): string {
+    const p = new Printer()
+    p.print(args)
+    return p.buf
+}
+
+export function Sprintln(...args: unknown[]): string {
+    const p = new Printer()
+    p.println(args)
+    return p.buf
+}
+
+// the Print functions return [bytes written, error]
+export function Printf(format: string, ...args: unknown[]): [number, Error | null] {
+    return write(Sprintf(format, ...args))
+}
+
+export function Print(...args: unknown[]): [number, Error | null] {
+    return write(Sprint(...args))
+}
+
+export function Println(...args: unknown[]): [number, Error | null] {
+    return write(Sprintln(...args))
+}
+
+// Errorf formats the error message, %w is formatted like %v
+export function Errorf(format: string, ...args: unknown[]): Error {
+    return new Error(Sprintf(format, ...args))
+}
+
+// pending holds the output not yet ended by a newline when there is
+// no process.stdout to write to and lines go to console.log instead
+let pending = ""
+
+function write(s: string): [number, Error | null] {
+    const stdout = (globalThis as any).process?.stdout
+    if (stdout) {
+        stdout.write(s)
+    } else {
+        const lines = (pending + s).split("\n")
+        pending = lines.pop() as string
+        for (const line of lines) {
+            console.log(line)
+        }
+    }
+    return [new TextEncoder().encode(s).length, null]
+}
+
+interface Flags {
+    minus: boolean
+    plus: boolean
+    sharp: boolean
+    space: boolean
+    zero: boolean
+    plusV: boolean
+    width?: number
+    prec?: number
+}
+
+function noFlags(): Flags {
+    return { minus: false, plus: false, sharp: false, space: false, zero: false, plusV: false }
+}
+
+// Printer formats like the printer of Go's fmt package
+class Printer {
+    buf = ""
+    private flags: Flags = noFlags()
+
+    print(args: unknown[]): void {
+        let prevString = false
+        args.forEach((arg, i) => {
+            const isString = kindOf(arg) === "string"
+            // spaces are added between operands when neither is a string
+            if (i > 0 && !isString && !prevString) {
+                this.buf += " "
+            }
+            this.printArg(arg, "v")
+            prevString = isString
+        })
+    }
+
+    println(args: unknown[]): void {
+        args.forEach((arg, i) => {
+            if (i > 0) {
+                this.buf += " "
+            }
+            this.printArg(arg, "v")
+        })
+        this.buf += "\n"
+    }
+
+    printf(format: string, args: unknown[]): void {
+        let argNum = 0
+        let i = 0
+        while (i < format.length) {
+            const start = i
+            while (i < format.length && format[i] !== "%") {
+                i++
+            }
+            this.buf += format.slice(start, i)
+            if (i >= format.length) {
+                break
+            }
+            i++
+
+            const f = noFlags()
+            for (; i < format.length; i++) {
+                const ch = format[i]
+                if (ch === "-") {
+                    f.minus = true
+                    f.zero = false
+                } else if (ch === "+") {
+                    f.plus = true
+                } else if (ch === "#") {
+                    f.sharp = true
+                } else if (ch === " ") {
+                    f.space = true
+                } else if (ch === "0") {
+                    f.zero = !f.minus
+                } else {
+                    break
+                }
+            }
+
+            // width and precision are numbers or * taking an argument
+            const num = (): number | undefined => {
+                if (format[i] === "*") {
+                    i++
+                    const arg = argNum < args.length ? unwrap(args[argNum++]).value : undefined
+                    return typeof arg === "number" ? arg : undefined
+                }
+                const m = /^\d+/.exec(format.slice(i))
+                if (!m) {
+                    return undefined
+                }
+                i += m[0].length
+                return Number(m[0])
+            }
+            f.width = num()
+            if (f.width !== undefined && f.width < 0) {
+                f.minus = true
+                f.zero = false
+                f.width = -f.width
+            }
+            if (format[i] === ".") {
+                i++
+                f.prec = num() ?? 0
+            }
+
+            if (i >= format.length) {
+                this.buf += "%!(NOVERB)"
+                break
+            }
+            const verb = String.fromCodePoint(format.codePointAt(i) as number)
+            i += verb.length
+            if (verb === "%") {
+                this.buf += "%"
+                continue
+            }
+            if (argNum >= args.length) {
+                this.buf += "%!" + verb + "(MISSING)"
+                continue
+            }
+            if (verb === "v" && f.plus) {
+                f.plus = false
+                f.plusV = true
+            }
+            this.flags = f
+            this.printArg(args[argNum++], verb === "w" ? "v" : verb)
+            this.flags = noFlags()
+        }
+
+        if (argNum < args.length) {
+            this.buf += "%!(EXTRA "
+            args.slice(argNum).forEach((arg, i) => {
+                if (i > 0) {
+                    this.buf += ", "
+                }
+                const { value, type } = unwrap(arg)
+                if (value === null || value === undefined) {
+                    this.buf += "<nil>"
+                    return
+                }
+                this.buf += typeName(value, type) + "="
+                this.printArg(arg, "v")
+            })
+            this.buf += ")"
+        }
+    }
+
+    printArg(arg: unknown, verb: string): void {
+        const { value, type, underlying } = unwrap(arg)
+        if (verb === "T") {
+            this.pad(typeName(value, type))
+            return
+        }
+        this.printValue(value, underlying ?? type, verb, 0, type)
+    }
+
+    // printValue formats v of the Go type t, inferred from v when
+    // not known. name is the type name shown for bad verbs.
+    printValue(v: unknown, t: string, verb: string, depth: number, name = t): void {
+        if (v instanceof Typed) {
+            this.printValue(v.value, v.underlying ?? v.type, verb, depth, v.type)
+            return
+        }
+        const kind = kindOf(v, t)
+        if (kind === "nil") {
+            if (verb === "v" || verb === "s") {
+                this.pad("<nil>")
+            } else {
+                this.badVerb(verb, v, name)
+            }
+            return
+        }
+        if (this.handleMethods(v, verb)) {
+            return
+        }
+        switch (kind) {
+            case "bool":
+                if (verb === "v" || verb === "t") {
+                    this.pad(String(v))
+                } else {
+                    this.badVerb(verb, v, name)
+                }
+                return
+            case "int":
+            case "uint":
+                this.printInt(v as number | bigint, verb, name)
+                return
+            case "float":
+                this.printFloat(v as number, /32$/.test(t) ? 32 : 64, verb, name)
+                return
+            case "string":
+                this.printString(v as string, verb, name)
+                return
+            case "slice": {
+                const elem = elemType(t)
+                const list = v instanceof Slice ? [...v] : ((v ?? []) as unknown[])
+                if ((elem === "uint8" || elem === "byte") && "sqxX".includes(verb)) {
+                    this.printString(new TextDecoder().decode(Uint8Array.from(list as number[])), verb, name)
+                    return
+                }
+                this.buf += "["
+                list.forEach((e, i) => {
+                    if (i > 0) {
+                        this.buf += " "
+                    }
+                    this.printValue(e, elem, verb, depth + 1)
+                })
+                this.buf += "]"
+                return
+            }
+            case "map": {
+                const [key, elem] = mapTypes(t)
+                const map = (v ?? new Map()) as Map<unknown, unknown> | GoMap<unknown, unknown>
+                const entries = [...map.entries()].sort((a, b) =>
+                    compareKeys(a[0], b[0]),
+                )
+                this.buf += "map["
+                entries.forEach(([k, e], i) => {
+                    if (i > 0) {
+                        this.buf += " "
+                    }
+                    this.printValue(k, key, verb, depth + 1)
+                    this.buf += ":"
+                    this.printValue(e, elem, verb, depth + 1)
+                })
+                this.buf += "]"
+                return
+            }
+            case "ptr":
+                if (depth === 0 && typeof v === "object") {
+                    // pointers to structs at the top level show the struct
+                    this.buf += "&"
+                    this.printValue(v, t.slice(1), verb, depth + 1)
+                    return
+                }
+                this.pad("0xc000010000")
+                return
+            case "struct": {
+                const fields = Object.entries(v as object)
+                // classes list the types of fields like nil slices
+                const { fieldTypes = {} } = (v as object).constructor as { fieldTypes?: Record<string, string> }
+                this.buf += "{"
+                fields.forEach(([field, e], i) => {
+                    if (i > 0) {
+                        this.buf += " "
+                    }
+                    if (this.flags.plusV) {
+                        this.buf += field + ":"
+                    }
+                    this.printValue(e, fieldTypes[field] ?? "", verb, depth + 1)
+                })
+                this.buf += "}"
+                return
+            }
+        }
+        this.pad(String(v))
+    }
+
+    // handleMethods formats errors and values with a String method
+    // by calling them, as Go does for the error and Stringer types
+    handleMethods(v: unknown, verb: string): boolean {
+        if (!"vsxXq".includes(verb) || v === null || typeof v !== "object") {
+            return false
+        }
+        let s: string
+        if (v instanceof Error) {
+            s = v.message
+        } else if (typeof (v as any).Error === "function") {
+            s = (v as any).Error()
+        } else if (typeof (v as any).String === "function") {
+            s = (v as any).String()
+        } else {
+            return false
+        }
+        this.printString(s, verb, "string")
+        return true
+    }
+
+    printInt(v: number | bigint, verb: string, name: string): void {
+        const f = this.flags
+        let n = BigInt(v)
+        const negative = n < 0n
+        if (negative) {
+            n = -n
+        }
+        let digits: string
+        let prefix = ""
+        switch (verb) {
+            case "v":
+            case "d":
+                digits = n.toString()
+                break
+            case "b":
+                digits = n.toString(2)
+                prefix = f.sharp ? "0b" : ""
+                break
+            case "o":
+            case "O":
+                digits = n.toString(8)
+                prefix = verb === "O" ? "0o" : f.sharp ? "0" : ""
+                break
+            case "x":
+                digits = n.toString(16)
+                prefix = f.sharp ? "0x" : ""
+                break
+            case "X":
+                digits = n.toString(16).toUpperCase()
+                prefix = f.sharp ? "0X" : ""
+                break
+            case "c":
+                this.pad(String.fromCodePoint(Number(v)))
+                return
+            case "q":
+                this.pad(quote(String.fromCodePoint(Number(v)), "'"))
+                return
+            case "U": {
+                const u = "U+" + n.toString(16).toUpperCase().padStart(4, "0")
+                this.pad(f.sharp ? u + " '" + String.fromCodePoint(Number(v)) + "'" : u)
+                return
+            }
+            default:
+                this.badVerb(verb, v, name)
+                return
+        }
+        if (f.prec !== undefined) {
+            digits = f.prec === 0 && n === 0n ? "" : digits.padStart(f.prec, "0")
+        }
+        const sign = negative ? "-" : f.plus ? "+" : f.space ? " " : ""
+        this.padNumber(sign + prefix, digits, f.prec === undefined)
+    }
+
+    printFloat(v: number, bits: number, verb: string, name: string): void {
+        const f = this.flags
+        if (!"vbeEfFgGxX".includes(verb)) {
+            this.badVerb(verb, v, name)
+            return
+        }
+        if (Number.isNaN(v)) {
+            this.pad(f.plus ? "+NaN" : f.space ? " NaN" : "NaN")
+            return
+        }
+        if (!Number.isFinite(v)) {
+            this.pad(v < 0 ? "-Inf" : f.space && !f.plus ? " Inf" : "+Inf")
+            return
+        }
+        const negative = v < 0 || Object.is(v, -0)
+        const x = Math.abs(v)
+        let s: string
+        switch (verb) {
+            case "e":
+            case "E":
+                s = exponent(x.toExponential(f.prec ?? 6))
+                break
+            case "f":
+            case "F":
+                s = x.toFixed(f.prec ?? 6)
+                break
+            default:
+                s = formatG(x, bits, f.prec, f.sharp)
+        }
+        if (verb === "E" || verb === "G") {
+            s = s.toUpperCase()
+        }
+        const sign = negative ? "-" : f.plus ? "+" : f.space ? " " : ""
+        this.padNumber(sign, s, true)
+    }
+
+    printString(s: string, verb: string, name: string): void {
+        const f = this.flags
+        switch (verb) {
+            case "v":
+            case "s":
+                if (f.prec !== undefined) {
+                    s = [...s].slice(0, f.prec).join("")
+                }
+                this.pad(s)
+                return
+            case "q":
+                this.pad(quote(s, '"'))
+                return
+            case "x":
+            case "X": {
+                let hex = [...new TextEncoder().encode(s)]
+                    .map((b) => b.toString(16).padStart(2, "0"))
+                    .join(f.space ? " " : "")
+                if (verb === "X") {
+                    hex = hex.toUpperCase()
+                }
+                this.pad(hex)
+                return
+            }
+        }
+        this.badVerb(verb, s, name)
+    }
+
+    badVerb(verb: string, v: unknown, name: string): void {
+        const flags = this.flags
+        this.flags = noFlags()
+        this.buf += "%!" + verb + "("
+        if (v === null || v === undefined) {
+            this.buf += "<nil>"
+        } else {
+            this.buf += typeName(v, name) + "="
+            this.printValue(v, name, "v", 0)
+        }
+        this.buf += ")"
+        this.flags = flags
+    }
+
+    // pad writes s padded to the width
+    pad(s: string): void {
+        const f = this.flags
+        const n = (f.width ?? 0) - [...s].length
+        if (n <= 0) {
+            this.buf += s
+        } else if (f.minus) {
+            this.buf += s + " ".repeat(n)
+        } else {
+            this.buf += (f.zero ? "0" : " ").repeat(n) + s
+        }
+    }
+
+    // padNumber writes a number, zeros pad between the sign and the digits
+    padNumber(sign: string, digits: string, zeroPad: boolean): void {
+        const f = this.flags
+        const n = (f.width ?? 0) - sign.length - digits.length
+        if (f.zero && zeroPad && !f.minus && n > 0) {
+            this.buf += sign + "0".repeat(n) + digits
+            return
+        }
+        this.pad(sign + digits)
+    }
+}
+
+function unwrap(arg: unknown): { value: unknown; type: string; underlying?: string } {
+    if (arg instanceof Typed) {
+        return { value: arg.value, type: arg.type, underlying: arg.underlying }
+    }
+    return { value: arg, type: "" }
+}
+
+type Kind = "nil" | "bool" | "int" | "uint" | "float" | "string" | "slice" | "map" | "ptr" | "struct" | "other"
+
+// kindOf returns the kind of the Go type t, or of the value
+// v if t is not known
+function kindOf(v: unknown, t = ""): Kind {
+    if (v instanceof Typed) {
+        return kindOf(v.value, v.underlying ?? v.type)
+    }
+    if (/^(u?int(8|16|32|64)?|uintptr|byte|rune)$/.test(t)) {
+        return t.startsWith("u") || t === "byte" ? "uint" : "int"
+    }
+    if (/^float(32|64)$/.test(t)) {
+        return "float"
+    }
+    if (t === "string" || t === "bool") {
+        return t
+    }
+    if (/^\[\d*\]/.test(t)) {
+        return "slice"
+    }
+    if (t.startsWith("map[")) {
+        return "map"
+    }
+    if (v === null || v === undefined) {
+        return "nil"
+    }
+    if (t.startsWith("*")) {
+        return "ptr"
+    }
+    switch (typeof v) {
+        case "boolean":
+            return "bool"
+        case "string":
+            return "string"
+        case "bigint":
+            return "int"
+        case "number":
+            return Number.isInteger(v) ? "int" : "float"
+        case "object":
+            if (Array.isArray(v) || v instanceof Slice) {
+                return "slice"
+            }
+            if (v instanceof Map || v instanceof GoMap) {
+                return "map"
+            }
+            return "struct"
+    }
+    return "other"
+}
+
+// typeName returns the Go type name shown by %T
+function typeName(v: unknown, t: string): string {
+    if (t !== "" && !/^(interface \{.*\}|any|error)$/.test(t)) {
+        return t.replace(/\bbyte\b/g, "uint8").replace(/\brune\b/g, "int32")
+    }
+    if (v === null || v === undefined) {
+        return "<nil>"
+    }
+    if (v instanceof Typed) {
+        return typeName(v.value, v.type)
+    }
+    if (v instanceof Error) {
+        return "*errors.errorString"
+    }
+    switch (kindOf(v)) {
+        case "bool":
+            return "bool"
+        case "string":
+            return "string"
+        case "int":
+            return typeof v === "bigint" ? "int64" : "int"
+        case "float":
+            return "float64"
+        case "slice":
+            return "[]interface {}"
+        case "map":
+            return "map[interface {}]interface {}"
+    }
+    const name = (v as object).constructor?.name
+    return name && name !== "Object" ? "main." + name : "struct {}"
+}
+
+// elemType returns the element type of a slice or array type
+function elemType(t: string): string {
+    const m = /^\[\d*\](.*)$/.exec(t)
+    return m ? m[1] : ""
+}
+
+// mapTypes returns the key and value types of a map type
+function mapTypes(t: string): [string, string] {
+    if (!t.startsWith("map[")) {
+        return ["", ""]
+    }
+    let depth = 0
+    for (let i = 3; i < t.length; i++) {
+        if (t[i] === "[") {
+            depth++
+        } else if (t[i] === "]" && --depth === 0) {
+            return [t.slice(4, i), t.slice(i + 1)]
+        }
+    }
+    return ["", ""]
+}
+
+// compareKeys orders map keys as Go prints them
+function compareKeys(a: unknown, b: unknown): number {
+    const x = a instanceof Typed ? a.value : a
+    const y = b instanceof Typed ? b.value : b
+    if (typeof x === "number" && typeof y === "number") {
+        return x - y
+    }
+    if (typeof x === "boolean" && typeof y === "boolean") {
+        return Number(x) - Number(y)
+    }
+    const s = String(x)
+    const u = String(y)
+    return s < u ? -1 : s > u ? 1 : 0
+}
+
+// exponent rewrites the exponent of JS like Go: e+5 is e+05
+function exponent(s: string): string {
+    return s.replace(/e([+-])(\d)$/, "e$10$2")
+}
+
+// formatG formats x like %g, with the shortest representation if
+// prec is not given. The exponent form is used for exponents
+// less than -4 or not less than the precision.
+function formatG(x: number, bits: number, prec: number | undefined, sharp: boolean): string {
+    let s: string
+    if (prec === undefined) {
+        s = bits === 32 ? shortest32(x) : x.toExponential()
+    } else {
+        s = x.toExponential(Math.max(prec, 1) - 1)
+    }
+    const [mantissa, exp] = s.split("e")
+    let digits = mantissa.replace(".", "")
+    if (!sharp) {
+        digits = digits.replace(/(.)0+$/, "$1")
+    }
+    const e = Number(exp)
+    let eprec = prec === undefined ? 6 : Math.max(prec, 1)
+    if (prec !== undefined && eprec > digits.length && digits.length >= e + 1) {
+        eprec = digits.length
+    }
+    if (e < -4 || e >= eprec) {
+        const frac = digits.length > 1 ? "." + digits.slice(1) : ""
+        return exponent(digits[0] + frac + "e" + (e < 0 ? "-" : "+") + Math.abs(e))
+    }
+    if (e < 0) {
+        return "0." + "0".repeat(-e - 1) + digits
+    }
+    const int = digits.slice(0, e + 1).padEnd(e + 1, "0")
+    const frac = digits.slice(e + 1)
+    return frac ? int + "." + frac : int
+}
+
+// shortest32 returns the shortest exponent form of x that
+// reads back as the same float32
+function shortest32(x: number): string {
+    for (let p = 1; p < 17; p++) {
+        const s = x.toExponential(p - 1)
+        if (Math.fround(Number(s)) === x) {
+            return s
+        }
+    }
+    return x.toExponential()
+}
+
+// quote quotes s like strconv.Quote, or like strconv.QuoteRune
+// with single quotes
+function quote(s: string, q: string): string {
+    let out = q
+    for (const ch of s) {
+        const r = ch.codePointAt(0) as number
+        switch (ch) {
+            case q:
+            case "\\":
+                out += "\\" + ch
+                continue
+            case "\x07":
+                out += "\\a"
+                continue
+            case "\b":
+                out += "\\b"
+                continue
+            case "\f":
+                out += "\\f"
+                continue
+            case "\n":
+                out += "\\n"
+                continue
+            case "\r":
+                out += "\\r"
+                continue
+            case "\t":
+                out += "\\t"
+                continue
+            case "\v":
+                out += "\\v"
+                continue
+        }
+        if (r < 0x20 || r === 0x7f) {
+            out += "\\x" + r.toString(16).padStart(2, "0")
+        } else if (r >= 0xd800 && r < 0xe000) {
+            out += "\\ufffd"
+        } else {
+            out += ch
+        }
+    }
+    return out + q
+}
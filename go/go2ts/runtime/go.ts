@@ -0,0 +1,342 @@
+// Go builtins without a JavaScript counterpart, used by the
+// code translated with go2ts
+
+// runes iterates s like ranging over a Go string: the index is
+// the offset in the UTF-8 encoding and the value is the rune
+export function* runes(s: string): Generator<[number, number]> {
+    let i = 0
+    for (const ch of s) {
+        const r = ch.codePointAt(0) as number
+        yield [i, r]
+        i += r < 0x80 ? 1 : r < 0x800 ? 2 : r < 0x10000 ? 3 : 4
+    }
+}
+
+// strings are JS strings, which Go indexes and slices by the
+// bytes of their UTF-8 encoding, like the offsets of runes
+const encoder = new TextEncoder()
+const decoder = new TextDecoder()
+let lastString = ""
+let lastBytes = new Uint8Array(0)
+
+// utf8 encodes s, the last string is kept for loops indexing it
+function utf8(s: string): Uint8Array {
+    if (s !== lastString) {
+        lastString = s
+        lastBytes = encoder.encode(s)
+    }
+    return lastBytes
+}
+
+// byteAt returns s[i]
+export function byteAt(s: string, i: number): number {
+    const b = utf8(s)
+    if (i < 0 || i >= b.length) {
+        throw new Error(`runtime error: index out of range [${i}] with length ${b.length}`)
+    }
+    return b[i]
+}
+
+// substr returns s[low:high], cutting a rune in the middle gives
+// U+FFFD where Go keeps the bytes
+export function substr(s: string, low = 0, high?: number): string {
+    const b = utf8(s)
+    high ??= b.length
+    if (low < 0 || high < low || b.length < high) {
+        throw new Error(`runtime error: slice bounds out of range [${low}:${high}] with length ${b.length}`)
+    }
+    if (b.length === s.length) {
+        return s.slice(low, high)
+    }
+    return decoder.decode(b.subarray(low, high))
+}
+
+// Chan is a channel used without other goroutines, so operations
+// that would block in Go fail instead
+export class Chan<T> {
+    private buf: T[] = []
+    private closed = false
+
+    constructor(
+        readonly cap: number,
+        private readonly zero: T,
+    ) {}
+
+    get length(): number {
+        return this.buf.length
+    }
+
+    send(v: T): void {
+        if (this.closed) {
+            throw new Error("send on closed channel")
+        }
+        if (this.buf.length >= this.cap) {
+            throw new Error("all goroutines are asleep - deadlock!")
+        }
+        this.buf.push(v)
+    }
+
+    recv(): T {
+        return this.recv2()[0]
+    }
+
+    // recv2 receives like `v, ok := <-ch`
+    recv2(): [T, boolean] {
+        if (this.buf.length > 0) {
+            return [this.buf.shift() as T, true]
+        }
+        if (this.closed) {
+            return [this.zero, false]
+        }
+        throw new Error("all goroutines are asleep - deadlock!")
+    }
+
+    // recvReady and sendReady report whether a select case can
+    // proceed, sending proceeds to fail on a closed channel
+    recvReady(): boolean {
+        return this.buf.length > 0 || this.closed
+    }
+
+    sendReady(): boolean {
+        return this.closed || this.buf.length < this.cap
+    }
+
+    close(): void {
+        if (this.closed) {
+            throw new Error("close of closed channel")
+        }
+        this.closed = true
+    }
+
+    // iterating receives until the channel is closed
+    *[Symbol.iterator](): Iterator<T> {
+        for (;;) {
+            const [v, ok] = this.recv2()
+            if (!ok) {
+                return
+            }
+            yield v
+        }
+    }
+}
+
+// canRecv and canSend report whether a select case on ch can
+// proceed, a nil channel never can
+export function canRecv(ch: Chan<unknown> | null): boolean {
+    return ch !== null && ch.recvReady()
+}
+
+export function canSend(ch: Chan<unknown> | null): boolean {
+    return ch !== null && ch.sendReady()
+}
+
+// Slice is a Go slice: length elements of the backing array from
+// offset on, with room to append up to cap elements. A nil slice
+// is null, which the functions below accept.
+export class Slice<T> {
+    constructor(
+        readonly array: T[],
+        readonly offset = 0,
+        readonly length = array.length - offset,
+        readonly cap = array.length - offset,
+    ) {}
+
+    get(i: number): T {
+        this.check(i)
+        return this.array[this.offset + i]
+    }
+
+    set(i: number, v: T): void {
+        this.check(i)
+        this.array[this.offset + i] = v
+    }
+
+    private check(i: number): void {
+        if (i < 0 || i >= this.length) {
+            throw new Error(`runtime error: index out of range [${i}] with length ${this.length}`)
+        }
+    }
+
+    // ranging over a slice reads the elements as it goes, up to
+    // the length at the start
+    *[Symbol.iterator](): Iterator<T> {
+        for (let i = 0; i < this.length; i++) {
+            yield this.array[this.offset + i]
+        }
+    }
+
+    *entries(): Generator<[number, T]> {
+        for (let i = 0; i < this.length; i++) {
+            yield [i, this.array[this.offset + i]]
+        }
+    }
+
+    *keys(): Generator<number> {
+        for (let i = 0; i < this.length; i++) {
+            yield i
+        }
+    }
+}
+
+export function len(x: string | Slice<unknown> | Map<unknown, unknown> | null): number {
+    if (x === null) {
+        return 0
+    }
+    if (typeof x === "string") {
+        return utf8(x).length
+    }
+    return x instanceof Map ? x.size : x.length
+}
+
+export function cap(s: Slice<unknown> | null): number {
+    return s === null ? 0 : s.cap
+}
+
+// slice returns s[low:high:max], arrays are sliced in place
+export function slice<T>(s: Slice<T> | T[] | null, low = 0, high?: number, max?: number): Slice<T> | null {
+    if (Array.isArray(s)) {
+        s = new Slice(s)
+    }
+    const c = s === null ? 0 : s.cap
+    high ??= s === null ? 0 : s.length
+    max ??= c
+    if (low < 0 || high < low || max < high || c < max) {
+        throw new Error(`runtime error: slice bounds out of range [${low}:${high}] with capacity ${c}`)
+    }
+    if (s === null) {
+        return null
+    }
+    return new Slice(s.array, s.offset + low, high - low, max - low)
+}
+
+// append writes to the backing array of s if it has room, and to
+// a new array of twice the capacity otherwise, as Go does
+export function append<T>(s: Slice<T> | null, ...values: T[]): Slice<T> | null {
+    if (values.length === 0) {
+        return s
+    }
+    const n = len(s) + values.length
+    if (s !== null && n <= s.cap) {
+        values.forEach((v, i) => {
+            s.array[s.offset + s.length + i] = v
+        })
+        return new Slice(s.array, s.offset, n, s.cap)
+    }
+    const array = s === null ? [] : s.array.slice(s.offset, s.offset + s.length)
+    array.push(...values)
+    return new Slice(array, 0, n, Math.max(n, cap(s) * 2))
+}
+
+// copy copies the elements that fit from src to dst
+export function copy<T>(dst: Slice<T> | null, src: Slice<T> | null): number {
+    const n = Math.min(len(dst), len(src))
+    if (dst === null || src === null) {
+        return 0
+    }
+    // src may overlap dst
+    const values = src.array.slice(src.offset, src.offset + n)
+    values.forEach((v, i) => {
+        dst.array[dst.offset + i] = v
+    })
+    return n
+}
+
+// entries, keys and values iterate a slice or a map, which
+// may be nil
+export function entries<T>(x: Slice<T> | null): Iterable<[number, T]>
+export function entries<K, V>(x: Map<K, V> | null): Iterable<[K, V]>
+export function entries(x: Slice<unknown> | Map<unknown, unknown> | null): Iterable<[unknown, unknown]> {
+    return x === null ? [] : x.entries()
+}
+
+export function keys<T>(x: Slice<T> | null): Iterable<number>
+export function keys<K, V>(x: Map<K, V> | null): Iterable<K>
+export function keys(x: Slice<unknown> | Map<unknown, unknown> | null): Iterable<unknown> {
+    return x === null ? [] : x.keys()
+}
+
+export function values<T>(x: Slice<T> | null): Iterable<T>
+export function values<K, V>(x: Map<K, V> | null): Iterable<V>
+export function values(x: Slice<unknown> | Map<unknown, unknown> | null): Iterable<unknown> {
+    return x === null ? [] : x instanceof Map ? x.values() : x
+}
+
+// Map is a Go map, reading a missing key gives the zero value.
+// Keys are compared by value if byValue is set, which is needed
+// for struct and array keys.
+export class Map<K, V> {
+    private readonly m = new globalThis.Map<unknown, [K, V]>()
+
+    constructor(
+        private readonly zero: V,
+        entries: Iterable<[K, V]> = [],
+        private readonly byValue = false,
+    ) {
+        for (const [k, v] of entries) {
+            this.set(k, v)
+        }
+    }
+
+    get size(): number {
+        return this.m.size
+    }
+
+    get(k: K): V {
+        return this.get2(k)[0]
+    }
+
+    // get2 reads like `v, ok := m[k]`
+    get2(k: K): [V, boolean] {
+        const entry = this.m.get(this.key(k))
+        return entry === undefined ? [this.zero, false] : [entry[1], true]
+    }
+
+    has(k: K): boolean {
+        return this.m.has(this.key(k))
+    }
+
+    set(k: K, v: V): void {
+        this.m.set(this.key(k), [k, v])
+    }
+
+    delete(k: K): void {
+        this.m.delete(this.key(k))
+    }
+
+    private key(k: K): unknown {
+        return this.byValue ? JSON.stringify(k) : k
+    }
+
+    [Symbol.iterator](): Iterator<[K, V]> {
+        return this.m.values()
+    }
+
+    entries(): IterableIterator<[K, V]> {
+        return this.m.values()
+    }
+
+    *keys(): Generator<K> {
+        for (const [k] of this.m.values()) {
+            yield k
+        }
+    }
+
+    *values(): Generator<V> {
+        for (const [, v] of this.m.values()) {
+            yield v
+        }
+    }
+}
+
+// equal compares struct and array values field by field
+export function equal(a: unknown, b: unknown): boolean {
+    if (a === b) {
+        return true
+    }
+    if (typeof a !== "object" || typeof b !== "object" || a === null || b === null) {
+        return false
+    }
+    const x = Object.values(a)
+    const y = Object.values(b)
+    return x.length === y.length && x.every((v, i) => equal(v, y[i]))
+}
@@ -0,0 +1,71 @@
+package main
+
+import "fmt"
+
+const greeting = "hi"
+
+const (
+	KB = 1 << (10 * (iota + 1))
+	MB
+)
+
+var counter int
+
+func divmod(a, b int) (q, r int) {
+	q = a / b
+	r = a % b
+	return
+}
+
+func pair() (string, error) {
+	return "pair", nil
+}
+
+func main() {
+	var a int
+	var b, c = 1, 2.5
+	x := 10
+	x += 5
+	x *= 2
+	x /= 4
+	x &^= 4
+	counter++
+	counter++
+	counter--
+	a, b = b, a
+
+	s := greeting
+	s += " there"
+	q, r := divmod(17, 5)
+	name, err := pair()
+	_, err = pair()
+	_ = name
+
+	nums := []int{1, 2, 3}
+	nums[0], nums[2] = nums[2], nums[0]
+
+	n := x
+	{
+		n := n * 2
+		x := "shadow"
+		fmt.Println(n, x)
+	}
+	double := func(v int) int {
+		v++
+		return v * 2
+	}
+	fmt.Println(a, b, c, x, counter, s)
+	fmt.Println(q, r, name, err == nil)
+	fmt.Println(nums[0], nums[1], nums[2], KB, MB)
+	fmt.Println(n, double(n), 7/2, 7.0/2)
+
+	var u8 uint8 = 250
+	u8 += 10
+	var i8 int8 = 127
+	i8++
+	var i32 int32 = 1 << 30
+	i32 *= 4
+	var u32 uint32 = 3
+	u32 -= 5
+	fmt.Println(u8, i8, i32, u32, u32>>1, ^u8, int8(u8+200), uint16(-i8))
+}
@@ -0,0 +1,72 @@
+import * as fmt from "go2ts/runtime/fmt"
+import * as go from "go2ts/runtime/go"
+const greeting = "hi"
+export const KB = 1024
+export const MB = 1048576
+let counter: number = 0
+function divmod(a: number, b: number): [number, number] {
+    let q: number = 0
+    let r: number = 0
+    q = Math.trunc(a / b)
+    r = a % b
+    return [q, r]
+}
+function pair(): [string, Error | null] {
+    return ["pair", null]
+}
+function main() {
+    let a: number = 0
+    let b = 1
+    const c = 2.5
+    let x = 10
+    x += 5
+    x *= 2
+    x = Math.trunc(x / 4)
+    x = x & ~4
+    counter++
+    counter++
+    counter--
+    ;[a, b] = [b, a]
+    let s = greeting
+    s += " there"
+    const [q, r] = divmod(17, 5)
+    let [name, err] = pair()
+    ;[, err] = pair()
+    const nums = new go.Slice<number>([1, 2, 3])
+    const [_v0, _v1] = [nums.get(2), nums.get(0)]
+    nums.set(0, _v0)
+    nums.set(2, _v1)
+    const n = x
+    {
+        const n_1 = n * 2
+        const x_1 = "shadow"
+        fmt.Println(n_1, x_1)
+    }
+    const double = (v: number): number => {
+        v++
+        return v * 2
+    }
+    fmt.Println(a, b, fmt.typed(c, "float64"), x, counter, s)
+    fmt.Println(q, r, name, err === null)
+    fmt.Println(nums.get(0), nums.get(1), nums.get(2), KB, MB)
+    fmt.Println(n, double(n), 3, fmt.typed(3.5, "float64"))
+    let u8: number = 250
+    u8 = (u8 + 10) & 0xff
+    let i8: number = 127
+    i8 = ((i8 + 1) << 24) >> 24
+    let i32: number = 1073741824
+    i32 = Math.imul(i32, 4)
+    let u32: number = 3
+    u32 = (u32 - 5) >>> 0
+    fmt.Println(
+        fmt.typed(u8, "uint8"),
+        fmt.typed(i8, "int8"),
+        fmt.typed(i32, "int32"),
+        fmt.typed(u32, "uint32"),
+        fmt.typed(u32 >>> 1, "uint32"),
+        fmt.typed(~u8 & 0xff, "uint8"),
+        fmt.typed((((u8 + 200) & 0xff) << 24) >> 24, "int8"),
+        fmt.typed(((-i8 << 24) >> 24) & 0xffff, "uint16"),
+    )
+}
+main()
\ No newline at end of file
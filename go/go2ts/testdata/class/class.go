@@ -0,0 +1,74 @@
+package main
+
+import "fmt"
+
+type Point struct {
+	X, Y int
+}
+
+func (p Point) Add(q Point) Point {
+	p.X += q.X
+	p.Y += q.Y
+	return p
+}
+
+func (p *Point) Scale(k int) {
+	p.X *= k
+	p.Y *= k
+}
+
+func (p Point) Sum() int {
+	return p.X + p.Y
+}
+
+type Named struct {
+	Point
+	Name string
+}
+
+func (n *Named) Rename(name string) *Named {
+	n.Name = name
+	return n
+}
+
+type Celsius float64
+
+func (c Celsius) Fahrenheit() float64 {
+	return float64(c)*9/5 + 32
+}
+
+func main() {
+	p := Point{1, 2}
+	q := p.Add(Point{X: 10})
+	fmt.Println(p.X, p.Y, q.X, q.Y)
+
+	q.Scale(3)
+	fmt.Println(q.Sum())
+
+	n := &Named{Point: Point{Y: 5}, Name: "origin"}
+	n.Scale(2)
+	fmt.Println(n.Rename("moved").Name, n.X, n.Y, n.Sum())
+
+	var zero Named
+	fmt.Println(zero.Name == "", zero.Point.X)
+
+	scale := n.Scale
+	scale(10)
+	fmt.Println(n.Y)
+
+	temp := Celsius(100)
+	fmt.Println(temp.Fahrenheit())
+
+	type pair struct {
+		key   string
+		value int
+	}
+	kv := pair{"a", 1}
+	fmt.Println(kv.key, kv.value)
+
+	anon := struct {
+		On  bool
+		Max int
+	}{On: true}
+	fmt.Println(anon.On, anon.Max)
+}
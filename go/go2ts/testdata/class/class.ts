@@ -0,0 +1,73 @@
+import * as fmt from "go2ts/runtime/fmt"
+export class Point {
+    X: number = 0
+    Y: number = 0
+    constructor(init?: Partial<Point>) {
+        Object.assign(this, init)
+    }
+    clone(): Point {
+        return new Point(this)
+    }
+    Add(q: Point): Point {
+        const p = this.clone()
+        p.X += q.X
+        p.Y += q.Y
+        return p
+    }
+    Scale(k: number) {
+        this.X *= k
+        this.Y *= k
+    }
+    Sum(): number {
+        return this.X + this.Y
+    }
+}
+export class Named {
+    Point: Point = new Point()
+    Name: string = ""
+    constructor(init?: Partial<Named>) {
+        Object.assign(this, init)
+    }
+    clone(): Named {
+        return new Named({ ...this, Point: this.Point.clone() })
+    }
+    Rename(name: string): Named | null {
+        this.Name = name
+        return this
+    }
+}
+export function Celsius_Fahrenheit(c: number): number {
+    return c * 9 / 5 + 32
+}
+function main() {
+    const p = new Point({ X: 1, Y: 2 })
+    const q = p.Add(new Point({ X: 10 }))
+    fmt.Println(p.X, p.Y, q.X, q.Y)
+    q.Scale(3)
+    fmt.Println(q.Sum())
+    const n = new Named({ Point: new Point({ Y: 5 }), Name: "origin" })
+    n.Point.Scale(2)
+    fmt.Println(n.Rename("moved").Name, n.Point.X, n.Point.Y, n.Point.Sum())
+    const zero: Named = new Named()
+    fmt.Println(zero.Name === "", zero.Point.X)
+    const scale = n.Point.Scale.bind(n.Point)
+    scale(10)
+    fmt.Println(n.Point.Y)
+    const temp = 100
+    fmt.Println(fmt.typed(Celsius_Fahrenheit(temp), "float64"))
+    class pair {
+        key: string = ""
+        value: number = 0
+        constructor(init?: Partial<pair>) {
+            Object.assign(this, init)
+        }
+        clone(): pair {
+            return new pair(this)
+        }
+    }
+    const kv = new pair({ key: "a", value: 1 })
+    fmt.Println(kv.key, kv.value)
+    const anon = { On: true, Max: 0 }
+    fmt.Println(anon.On, anon.Max)
+}
+main()
\ No newline at end of file
@@ -0,0 +1,120 @@
+package main
+
+import "fmt"
+
+func classify(n int) string {
+	switch {
+	case n < 0:
+		return "negative"
+	case n == 0:
+		return "zero"
+	case n < 10:
+		return "small"
+	default:
+		return "large"
+	}
+}
+
+func grade(score int) string {
+	var g string
+	switch score / 10 {
+	case 10, 9:
+		g = "A"
+	case 8:
+		g = "B"
+	case 7:
+		fallthrough
+	case 6:
+		g = "C"
+	default:
+		g = "F"
+	}
+	return g
+}
+
+func parse(s string) (int, bool) {
+	if s == "" {
+		return 0, false
+	}
+	return len(s), true
+}
+
+func main() {
+	if n, ok := parse("four"); ok {
+		fmt.Println("parsed", n)
+	} else if n > 10 {
+		fmt.Println("too long")
+	} else {
+		fmt.Println("failed")
+	}
+
+	sum := 0
+	for i := 0; i < 5; i++ {
+		if i%2 == 0 {
+			continue
+		}
+		sum += i
+	}
+	for i, j := 0, 10; i < j; i, j = i+1, j-2 {
+		sum += j - i
+	}
+	count := 0
+	for count < 3 {
+		count++
+	}
+	for {
+		count--
+		if count == 0 {
+			break
+		}
+	}
+	fmt.Println(sum, count)
+
+	for _, n := range []int{-3, 0, 7, 42} {
+		fmt.Println(n, classify(n), grade(n+60))
+	}
+
+outer:
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			switch {
+			case j == 2:
+				continue outer
+			case i == 2:
+				break outer
+			}
+			fmt.Println(i, j)
+		}
+	}
+
+	words := []string{"a", "bb", "ccc"}
+rows:
+	for i := range len(words) {
+		for j := 0; j < 3; j++ {
+			if j == i {
+				continue rows
+			}
+			fmt.Println("row", i, j)
+		}
+	}
+
+	switch x := grade(95); x {
+	case "A":
+		msg := "top"
+		fmt.Println(x, msg)
+	case "B":
+		msg := "good"
+		fmt.Println(x, msg)
+	}
+
+	ch := make(chan string, 1)
+	ch <- "x"
+	for i := 0; i < 2; i++ {
+		select {
+		case v := <-ch:
+			fmt.Println("got", v)
+		default:
+			fmt.Println("empty")
+		}
+	}
+}
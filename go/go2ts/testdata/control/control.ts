@@ -0,0 +1,127 @@
+import * as fmt from "go2ts/runtime/fmt"
+import * as go from "go2ts/runtime/go"
+function classify(n: number): string {
+    switch (true) {
+        case n < 0:
+            return "negative"
+        case n === 0:
+            return "zero"
+        case n < 10:
+            return "small"
+        default:
+            return "large"
+    }
+}
+function grade(score: number): string {
+    let g: string = ""
+    switch (Math.trunc(score / 10)) {
+        case 10:
+        case 9:
+            g = "A"
+            break
+        case 8:
+            g = "B"
+            break
+        case 7:
+        case 6:
+            g = "C"
+            break
+        default:
+            g = "F"
+    }
+    return g
+}
+function parse(s: string): [number, boolean] {
+    if (s === "") {
+        return [0, false]
+    }
+    return [go.len(s), true]
+}
+function main() {
+    {
+        const [n, ok] = parse("four")
+        if (ok) {
+            fmt.Println("parsed", n)
+        } else if (n > 10) {
+            fmt.Println("too long")
+        } else {
+            fmt.Println("failed")
+        }
+    }
+    let sum = 0
+    for (let i = 0; i < 5; i++) {
+        if (i % 2 === 0) {
+            continue
+        }
+        sum += i
+    }
+    for (let i = 0, j = 10; i < j; [i, j] = [i + 1, j - 2]) {
+        sum += j - i
+    }
+    let count = 0
+    while (count < 3) {
+        count++
+    }
+    while (true) {
+        count--
+        if (count === 0) {
+            break
+        }
+    }
+    fmt.Println(sum, count)
+    for (const n of go.values(new go.Slice<number>([-3, 0, 7, 42]))) {
+        fmt.Println(n, classify(n), grade(n + 60))
+    }
+    outer: for (let i = 0; i < 3; i++) {
+        for (let j = 0; j < 3; j++) {
+            switch (true) {
+                case j === 2:
+                    continue outer
+                case i === 2:
+                    break outer
+            }
+            fmt.Println(i, j)
+        }
+    }
+    const words = new go.Slice<string>(["a", "bb", "ccc"])
+    {
+        const _n = go.len(words)
+        rows: for (let i = 0; i < _n; i++) {
+            for (let j = 0; j < 3; j++) {
+                if (j === i) {
+                    continue rows
+                }
+                fmt.Println("row", i, j)
+            }
+        }
+    }
+    {
+        const x = grade(95)
+        switch (x) {
+            case "A": {
+                const msg = "top"
+                fmt.Println(x, msg)
+                break
+            }
+            case "B": {
+                const msg = "good"
+                fmt.Println(x, msg)
+            }
+        }
+    }
+    const ch = new go.Chan<string>(1, "")
+    ch.send("x")
+    for (let i = 0; i < 2; i++) {
+        switch (true) {
+            case go.canRecv(ch): {
+                const v = ch.recv()
+                fmt.Println("got", v)
+                break
+            }
+            default: {
+                fmt.Println("empty")
+            }
+        }
+    }
+}
+main()
\ No newline at end of file
@@ -0,0 +1,60 @@
+package main
+
+import "fmt"
+
+type Celsius float64
+
+type Point struct {
+	X, Y int
+}
+
+type Box struct {
+	Label string
+	Size  int
+}
+
+type Tagged struct {
+	Name  string
+	Tags  []string
+	Attrs map[string]int
+	Score float64
+}
+
+func (b Box) String() string {
+	return "box " + b.Label
+}
+
+func div(a, b int) (int, int) {
+	return a / b, a % b
+}
+
+func main() {
+	fmt.Println("int", 42, "float", 2.5, true)
+	fmt.Print("a", 1, 2, "b", "\n")
+	fmt.Println(1e6, 123456.0)
+
+	t := Celsius(36.6)
+	fmt.Printf("%v %T %.2f\n", t, t, t)
+	fmt.Printf("%5d|%-5d|%05d|%x|%X\n", 42, 42, -42, 255, 255)
+	fmt.Printf("%8.3f|%e\n", 3.14159, 123456.789)
+	fmt.Printf("%g|%g\n", 0.00001, 100000.0)
+	fmt.Printf("%s|%q|%5s|%-5s|%.2s\n", "go", "a\"b", "ts", "ts", "hi")
+	fmt.Printf("%c|%q|%U\n", 'G', 'o', 'G')
+
+	p := Point{1, 2}
+	fmt.Printf("%v %+v %T\n", p, &p, p)
+	fmt.Println(Box{Label: "small"}, []int{1, 2})
+	m := map[string]int{"a": 1}
+	fmt.Println(m)
+
+	err := fmt.Errorf("bad value %d", 7)
+	fmt.Println(err)
+	s := fmt.Sprintf("%d%%", 50)
+	fmt.Println(s, fmt.Sprint("x", 1, 2, "y"))
+	fmt.Println(div(7, -2))
+	a := Tagged{Name: "a"}
+	b := Tagged{Tags: []string{"x"}, Score: 1e6}
+	fmt.Printf("%v %+v\n", a, b)
+	fmt.Printf("%d %s\n", 1)
+	fmt.Printf("%z\n", 3)
+}
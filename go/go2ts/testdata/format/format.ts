@@ -0,0 +1,103 @@
+import * as fmt from "go2ts/runtime/fmt"
+import * as go from "go2ts/runtime/go"
+export class Point {
+    X: number = 0
+    Y: number = 0
+    constructor(init?: Partial<Point>) {
+        Object.assign(this, init)
+    }
+    clone(): Point {
+        return new Point(this)
+    }
+}
+export class Box {
+    Label: string = ""
+    Size: number = 0
+    constructor(init?: Partial<Box>) {
+        Object.assign(this, init)
+    }
+    clone(): Box {
+        return new Box(this)
+    }
+    String(): string {
+        return "box " + this.Label
+    }
+}
+export class Tagged {
+    Name: string = ""
+    Tags: go.Slice<string> = null
+    Attrs: go.Map<string, number> = null
+    Score: number = 0
+    static readonly fieldTypes = {
+        Tags: "[]string",
+        Attrs: "map[string]int",
+        Score: "float64",
+    }
+    constructor(init?: Partial<Tagged>) {
+        Object.assign(this, init)
+    }
+    clone(): Tagged {
+        return new Tagged(this)
+    }
+}
+function div(a: number, b: number): [number, number] {
+    return [Math.trunc(a / b), a % b]
+}
+function main() {
+    fmt.Println("int", 42, "float", fmt.typed(2.5, "float64"), true)
+    fmt.Print("a", 1, 2, "b", "\n")
+    fmt.Println(fmt.typed(1e6, "float64"), fmt.typed(123456.0, "float64"))
+    const t = 36.6
+    fmt.Printf(
+        "%v %T %.2f\n",
+        fmt.typed(t, "main.Celsius", "float64"),
+        fmt.typed(t, "main.Celsius", "float64"),
+        fmt.typed(t, "main.Celsius", "float64"),
+    )
+    fmt.Printf("%5d|%-5d|%05d|%x|%X\n", 42, 42, -42, 255, 255)
+    fmt.Printf(
+        "%8.3f|%e\n",
+        fmt.typed(3.14159, "float64"),
+        fmt.typed(123456.789, "float64"),
+    )
+    fmt.Printf(
+        "%g|%g\n",
+        fmt.typed(0.00001, "float64"),
+        fmt.typed(100000.0, "float64"),
+    )
+    fmt.Printf("%s|%q|%5s|%-5s|%.2s\n", "go", "a\"b", "ts", "ts", "hi")
+    fmt.Printf(
+        "%c|%q|%U\n",
+        fmt.typed(71, "rune"),
+        fmt.typed(111, "rune"),
+        fmt.typed(71, "rune"),
+    )
+    let p = new Point({ X: 1, Y: 2 })
+    fmt.Printf(
+        "%v %+v %T\n",
+        fmt.typed(p, "main.Point"),
+        fmt.typed(p, "*main.Point"),
+        fmt.typed(p, "main.Point"),
+    )
+    fmt.Println(
+        fmt.typed(new Box({ Label: "small" }), "main.Box"),
+        fmt.typed(new go.Slice<number>([1, 2]), "[]int"),
+    )
+    const m = new go.Map<string, number>(0, [["a", 1]])
+    fmt.Println(fmt.typed(m, "map[string]int"))
+    const err = fmt.Errorf("bad value %d", 7)
+    fmt.Println(err)
+    const s = fmt.Sprintf("%d%%", 50)
+    fmt.Println(s, fmt.Sprint("x", 1, 2, "y"))
+    fmt.Println(...div(7, -2))
+    const a = new Tagged({ Name: "a" })
+    const b = new Tagged({ Tags: new go.Slice<string>(["x"]), Score: 1e6 })
+    fmt.Printf(
+        "%v %+v\n",
+        fmt.typed(a, "main.Tagged"),
+        fmt.typed(b, "main.Tagged"),
+    )
+    fmt.Printf("%d %s\n", 1)
+    fmt.Printf("%z\n", 3)
+}
+main()
\ No newline at end of file
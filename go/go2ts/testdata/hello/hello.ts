@@ -1,4 +1,5 @@
+import * as fmt from "go2ts/runtime/fmt"
 function main() {
-    console.log("hello world\n")
+    fmt.Printf("hello world\n")
 }
 main()
\ No newline at end of file
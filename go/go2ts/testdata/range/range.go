@@ -0,0 +1,54 @@
+package main
+
+import "fmt"
+
+func main() {
+	words := []string{"go", "ts"}
+	for i, w := range words {
+		fmt.Println(i, w)
+	}
+	for i := range words {
+		fmt.Println("index", i)
+	}
+	total := 0
+	for range words {
+		total++
+	}
+
+	ages := map[string]int{"alice": 30, "bob": 25}
+	sum := 0
+	for _, age := range ages {
+		sum += age
+	}
+	for name := range ages {
+		total += len(name)
+	}
+	for name, age := range ages {
+		if name == "bob" {
+			fmt.Println(name, age)
+		}
+	}
+
+	for i, r := range "héllo, 世界" {
+		fmt.Println(i, r, string(r))
+	}
+	s := "héllo"
+	fmt.Println(len(s), s[1], s[3:])
+
+	for i := range 3 {
+		fmt.Println("int", i)
+	}
+	var last int
+	for last = range len(words) + 1 {
+	}
+
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	close(ch)
+	for v := range ch {
+		fmt.Println("recv", v)
+	}
+	v, ok := <-ch
+	fmt.Println(v, ok, total, sum, last)
+}
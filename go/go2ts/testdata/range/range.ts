@@ -0,0 +1,56 @@
+import * as fmt from "go2ts/runtime/fmt"
+import * as go from "go2ts/runtime/go"
+function main() {
+    const words = new go.Slice<string>(["go", "ts"])
+    for (const [i, w] of go.entries(words)) {
+        fmt.Println(i, w)
+    }
+    for (const i of go.keys(words)) {
+        fmt.Println("index", i)
+    }
+    let total = 0
+    for (const _ of go.values(words)) {
+        total++
+    }
+    const ages = new go.Map<string, number>(0, [
+        ["alice", 30],
+        ["bob", 25],
+    ])
+    let sum = 0
+    for (const age of go.values(ages)) {
+        sum += age
+    }
+    for (const name of go.keys(ages)) {
+        total += go.len(name)
+    }
+    for (const [name, age] of go.entries(ages)) {
+        if (name === "bob") {
+            fmt.Println(name, age)
+        }
+    }
+    for (const [i, r] of go.runes("héllo, 世界")) {
+        fmt.Println(i, fmt.typed(r, "rune"), String.fromCodePoint(r))
+    }
+    const s = "héllo"
+    fmt.Println(go.len(s), fmt.typed(go.byteAt(s, 1), "byte"), go.substr(s, 3))
+    for (let i = 0; i < 3; i++) {
+        fmt.Println("int", i)
+    }
+    let last: number = 0
+    {
+        const _n = go.len(words) + 1
+        for (let _i = 0; _i < _n; _i++) {
+            last = _i
+        }
+    }
+    const ch = new go.Chan<number>(3, 0)
+    ch.send(1)
+    ch.send(2)
+    ch.close()
+    for (const v of ch) {
+        fmt.Println("recv", v)
+    }
+    const [v, ok] = ch.recv2()
+    fmt.Println(v, ok, total, sum, last)
+}
+main()
\ No newline at end of file
@@ -0,0 +1,20 @@
+import * as fmt from "go2ts/runtime/fmt"
+export class Greet {
+    Name: string = ""
+    Word: string = ""
+    Time: any = {}
+    constructor(init?: Partial<Greet>) {
+        Object.assign(this, init)
+    }
+    clone(): Greet {
+        return new Greet({ ...this, Time: { ...this.Time } })
+    }
+    Sayit() {
+        fmt.Printf("%s %s\n", this.Word, this.Name)
+    }
+}
+function main() {
+    const g = new Greet({ Name: "word", Word: "hello" })
+    g.Sayit()
+}
+main()
\ No newline at end of file
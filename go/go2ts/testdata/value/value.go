@@ -0,0 +1,117 @@
+package main
+
+import "fmt"
+
+type Point struct {
+	X, Y int
+}
+
+type Line struct {
+	A, B Point
+	Tags []string
+}
+
+func sum(base int, nums ...int) int {
+	total := base
+	for _, n := range nums {
+		total += n
+	}
+	return total
+}
+
+func move(p Point) Point {
+	p.X++
+	return p
+}
+
+func (l *Line) Start() Point {
+	return l.A
+}
+
+func main() {
+	// slices share their backing array
+	s := []int{1, 2, 3, 4, 5}
+	t := s[1:3]
+	t[0] = 20
+	fmt.Println(s, t, len(t), cap(t))
+
+	// append writes in place while there is room
+	u := append(t, 30)
+	fmt.Println(s, u)
+	w := make([]int, 0, 2)
+	w = append(w, 1, 2)
+	x := append(w, 3)
+	x[0] = 100
+	fmt.Println(w, x, len(x))
+
+	n := copy(s, []int{7, 8})
+	fmt.Println(n, s)
+	fmt.Println(s[:2], s[3:])
+
+	var empty []int
+	fmt.Println(len(empty), empty == nil)
+	fmt.Println(sum(1), sum(1, 2, 3), sum(0, s...))
+	for range empty {
+		fmt.Println("unreachable")
+	}
+	empty = append(empty, 9)
+	fmt.Println(empty)
+
+	// maps read zero values for missing keys
+	ages := map[string]int{"alice": 30}
+	ages["bob"]++
+	age, ok := ages["carol"]
+	fmt.Println(ages["alice"], ages["bob"], age, ok, len(ages))
+	delete(ages, "alice")
+	fmt.Println(len(ages))
+
+	var none map[string]bool
+	fmt.Println(len(none))
+
+	visits := map[Point]int{}
+	visits[Point{1, 2}]++
+	visits[Point{1, 2}]++
+	visits[Point{2, 1}]++
+	fmt.Println(visits[Point{1, 2}], len(visits))
+
+	// structs and arrays are copied
+	a := Point{1, 2}
+	b := a
+	b.X = 10
+	c := move(a)
+	fmt.Println(a.X, b.X, c.X, a == Point{1, 2}, a != b)
+
+	l := Line{A: a, B: b}
+	start := l.Start()
+	start.Y = 50
+	l2 := l
+	l2.B.Y = 60
+	fmt.Println(l.A.Y, l.B.Y, l2.B.Y)
+
+	points := []Point{{1, 1}, {X: 2}}
+	for _, p := range points {
+		p.X = 0
+	}
+	points[1].Y = 7
+	fmt.Println(points[0].X, points[1].X, points[1].Y)
+
+	p := &points[0]
+	*p = Point{5, 5}
+	fmt.Println(points[0].X)
+	switch *p {
+	case Point{1, 1}:
+		fmt.Println("moved")
+	case Point{5, 5}:
+		fmt.Println("match")
+	}
+
+	arr := [3]int{1, 2, 3}
+	arr2 := arr
+	arr2[0] = 9
+	fmt.Println(arr[0], arr2[0], arr == [3]int{1, 2, 3})
+
+	word := "hello"
+	bs := []byte(word)
+	bs[0] = 'j'
+	fmt.Println(word[1:3], string(bs))
+}
@@ -0,0 +1,144 @@
+import * as fmt from "go2ts/runtime/fmt"
+import * as go from "go2ts/runtime/go"
+export class Point {
+    X: number = 0
+    Y: number = 0
+    constructor(init?: Partial<Point>) {
+        Object.assign(this, init)
+    }
+    clone(): Point {
+        return new Point(this)
+    }
+}
+export class Line {
+    A: Point = new Point()
+    B: Point = new Point()
+    Tags: go.Slice<string> = null
+    static readonly fieldTypes = { Tags: "[]string" }
+    constructor(init?: Partial<Line>) {
+        Object.assign(this, init)
+    }
+    clone(): Line {
+        return new Line({ ...this, A: this.A.clone(), B: this.B.clone() })
+    }
+    Start(): Point {
+        return this.A.clone()
+    }
+}
+function sum(base: number, nums: go.Slice<number>): number {
+    let total = base
+    for (const n of go.values(nums)) {
+        total += n
+    }
+    return total
+}
+function move(p: Point): Point {
+    p.X++
+    return p
+}
+function main() {
+    const s = new go.Slice<number>([1, 2, 3, 4, 5])
+    const t = go.slice(s, 1, 3)
+    t.set(0, 20)
+    fmt.Println(
+        fmt.typed(s, "[]int"),
+        fmt.typed(t, "[]int"),
+        go.len(t),
+        go.cap(t),
+    )
+    const u = go.append(t, 30)
+    fmt.Println(fmt.typed(s, "[]int"), fmt.typed(u, "[]int"))
+    let w = new go.Slice<number>(Array.from({ length: 2 }, () => 0), 0, 0)
+    w = go.append(w, 1, 2)
+    const x = go.append(w, 3)
+    x.set(0, 100)
+    fmt.Println(fmt.typed(w, "[]int"), fmt.typed(x, "[]int"), go.len(x))
+    const n = go.copy(s, new go.Slice<number>([7, 8]))
+    fmt.Println(n, fmt.typed(s, "[]int"))
+    fmt.Println(
+        fmt.typed(go.slice(s, 0, 2), "[]int"),
+        fmt.typed(go.slice(s, 3), "[]int"),
+    )
+    let empty: go.Slice<number> = null
+    fmt.Println(go.len(empty), empty === null)
+    fmt.Println(sum(1, null), sum(1, new go.Slice<number>([2, 3])), sum(0, s))
+    for (const _ of go.values(empty)) {
+        fmt.Println("unreachable")
+    }
+    empty = go.append(empty, 9)
+    fmt.Println(fmt.typed(empty, "[]int"))
+    const ages = new go.Map<string, number>(0, [["alice", 30]])
+    ages.set("bob", ages.get("bob") + 1)
+    const [age, ok] = ages.get2("carol")
+    fmt.Println(ages.get("alice"), ages.get("bob"), age, ok, go.len(ages))
+    ages.delete("alice")
+    fmt.Println(go.len(ages))
+    const none: go.Map<string, boolean> = null
+    fmt.Println(go.len(none))
+    const visits = new go.Map<Point, number>(0, [], true)
+    visits.set(
+        new Point({ X: 1, Y: 2 }),
+        visits.get(new Point({ X: 1, Y: 2 })) + 1,
+    )
+    visits.set(
+        new Point({ X: 1, Y: 2 }),
+        visits.get(new Point({ X: 1, Y: 2 })) + 1,
+    )
+    visits.set(
+        new Point({ X: 2, Y: 1 }),
+        visits.get(new Point({ X: 2, Y: 1 })) + 1,
+    )
+    fmt.Println(visits.get(new Point({ X: 1, Y: 2 })), go.len(visits))
+    const a = new Point({ X: 1, Y: 2 })
+    const b = a.clone()
+    b.X = 10
+    const c = move(a.clone())
+    fmt.Println(
+        a.X,
+        b.X,
+        c.X,
+        go.equal(a, new Point({ X: 1, Y: 2 })),
+        !go.equal(a, b),
+    )
+    const l = new Line({ A: a.clone(), B: b.clone() })
+    const start = l.Start()
+    start.Y = 50
+    const l2 = l.clone()
+    l2.B.Y = 60
+    fmt.Println(l.A.Y, l.B.Y, l2.B.Y)
+    const points = new go.Slice<Point>([
+        new Point({ X: 1, Y: 1 }),
+        new Point({ X: 2 }),
+    ])
+    for (let p of go.values(points)) {
+        p = p.clone()
+        p.X = 0
+    }
+    points.get(1).Y = 7
+    fmt.Println(points.get(0).X, points.get(1).X, points.get(1).Y)
+    const p = points.get(0)
+    Object.assign(p, new Point({ X: 5, Y: 5 }))
+    fmt.Println(points.get(0).X)
+    {
+        const _tag = p
+        switch (true) {
+            case go.equal(_tag, new Point({ X: 1, Y: 1 })):
+                fmt.Println("moved")
+                break
+            case go.equal(_tag, new Point({ X: 5, Y: 5 })):
+                fmt.Println("match")
+        }
+    }
+    const arr = [1, 2, 3]
+    const arr2 = [...arr]
+    arr2[0] = 9
+    fmt.Println(arr[0], arr2[0], go.equal(arr, [1, 2, 3]))
+    const word = "hello"
+    const bs = new go.Slice<number>(Array.from(new TextEncoder().encode(word)))
+    bs.set(0, 106)
+    fmt.Println(
+        go.substr(word, 1, 3),
+        new TextDecoder().decode(Uint8Array.from(go.values(bs))),
+    )
+}
+main()
\ No newline at end of file
@@ -19,6 +19,24 @@ func TestTranspileFile(t *testing.T) {
 		{
 			file: "hello/hello.go",
 		},
+		{
+			file: "assign/assign.go",
+		},
+		{
+			file: "control/control.go",
+		},
+		{
+			file: "range/range.go",
+		},
+		{
+			file: "class/class.go",
+		},
+		{
+			file: "format/format.go",
+		},
+		{
+			file: "value/value.go",
+		},
 		{
 			file: "type/type.go",
 			// skip: "not ready", //
@@ -56,3 +74,37 @@ func TestTranspileFile(t *testing.T) {
 		})
 	}
 }
+
+func TestTranspileUnsupported(t *testing.T) {
+	tests := []struct {
+		code    string
+		wantErr string
+	}{
+		{
+			code:    "package main\n\nfunc main() {\n\tvar x any = 1\n\tswitch x.(type) {\n\tcase int:\n\t}\n}\n",
+			wantErr: "main.go:5:2: unsupported statement *ast.TypeSwitchStmt",
+		},
+		{
+			code:    "package main\n\nfunc main() {\nloop:\n\tgoto loop\n}\n",
+			wantErr: "main.go:5:2: unsupported goto statement",
+		},
+		{
+			code:    "package main\n\nfunc main() {\n\tn := 1\n\tp := &n\n\t*p = 3\n}\n",
+			wantErr: "main.go:5:7: unsupported pointer to int",
+		},
+		{
+			code:    "package main\n\ntype Counter int\n\nfunc (c *Counter) Inc() {\n\t*c++\n}\n\nfunc main() {}\n",
+			wantErr: "main.go:6:2: unsupported pointer to main.Counter",
+		},
+		{
+			code:    "package main\n\nimport \"errors\"\n\nfunc main() {\n\tpanic(errors.New(\"x\"))\n}\n",
+			wantErr: "main.go:6:8: unsupported errors.New",
+		},
+	}
+	for _, tt := range tests {
+		_, err := TranspileCode(tt.code)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("TranspileCode() expect err %q, got: %v", tt.wantErr, err)
+		}
+	}
+}